@@ -10,7 +10,6 @@ require (
 	github.com/go-telegram-bot-api/telegram-bot-api/v5 v5.5.1
 	github.com/hashicorp/go-multierror v1.1.1
 	github.com/lib/pq v1.10.7
-	github.com/robfig/cron/v3 v3.0.0
 	github.com/rubenv/sql-migrate v1.5.2
 	github.com/russross/blackfriday v1.6.0
 	github.com/uptrace/bun/driver/pgdriver v1.1.16
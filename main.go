@@ -4,15 +4,20 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"net/http"
+	"net/url"
 	"os"
 	"os/signal"
 	"syscall"
 	"time"
+	_ "time/tzdata" // chat timezones must resolve in the alpine image
 
 	"github.com/caarlos0/env/v9"
 	"github.com/sushkevichd/day-guide-telegram-bot/pkg/googleai"
 	"github.com/sushkevichd/day-guide-telegram-bot/pkg/workers"
 
+	"github.com/sushkevichd/day-guide-telegram-bot/pkg/api"
+	"github.com/sushkevichd/day-guide-telegram-bot/pkg/astronomy"
 	"github.com/sushkevichd/day-guide-telegram-bot/pkg/auth"
 	"github.com/sushkevichd/day-guide-telegram-bot/pkg/database"
 	"github.com/sushkevichd/day-guide-telegram-bot/pkg/domain"
@@ -20,42 +25,57 @@ import (
 	"github.com/sushkevichd/day-guide-telegram-bot/pkg/formatter"
 	"github.com/sushkevichd/day-guide-telegram-bot/pkg/logger"
 	"github.com/sushkevichd/day-guide-telegram-bot/pkg/openexchangerates"
+	"github.com/sushkevichd/day-guide-telegram-bot/pkg/openmeteo"
 	"github.com/sushkevichd/day-guide-telegram-bot/pkg/openweathermap"
+	"github.com/sushkevichd/day-guide-telegram-bot/pkg/parser"
 	"github.com/sushkevichd/day-guide-telegram-bot/pkg/report"
 	"github.com/sushkevichd/day-guide-telegram-bot/pkg/repository"
 	"github.com/sushkevichd/day-guide-telegram-bot/pkg/service"
 	"github.com/sushkevichd/day-guide-telegram-bot/pkg/telegram"
 	"github.com/sushkevichd/day-guide-telegram-bot/pkg/telegram/command"
+	"github.com/sushkevichd/day-guide-telegram-bot/pkg/weatherprovider"
+	"github.com/sushkevichd/day-guide-telegram-bot/pkg/workers/httpserver"
 	"github.com/sushkevichd/day-guide-telegram-bot/pkg/workers/loader"
 	"github.com/sushkevichd/day-guide-telegram-bot/pkg/workers/plotbroadcaster"
 	telegramservice "github.com/sushkevichd/day-guide-telegram-bot/pkg/workers/telegram"
 )
 
-// Cron for daily messages - check cron on https://crontab.guru
+// Default delivery times of daily messages in the chat's timezone, overridden with /settings time
+var (
+	weatherDeliveryTimes      = []domain.DeliveryTime{{Hour: 9, Minute: 1}}
+	exchangeRateDeliveryTimes = []domain.DeliveryTime{{Hour: 9, Minute: 0}, {Hour: 18, Minute: 0}}
+	moonPhaseDeliveryTimes    = []domain.DeliveryTime{{Hour: 20, Minute: 30}}
+	holidayDeliveryTimes      = []domain.DeliveryTime{{Hour: 9, Minute: 2}}
+	newsDeliveryTimes         = []domain.DeliveryTime{{Hour: 12, Minute: 0}}
+
+	weeklyWeatherDeliveryTimes = []domain.DeliveryTime{{Hour: 19, Minute: 0}}
+	weeklyWeatherDeliveryDay   = time.Sunday
+)
+
+// Ways of receiving telegram updates
 const (
-	weatherDailyCron      = "1 6 * * *"    // At 09:01 UTC+3
-	exchangeRateDailyCron = "0 6,15 * * *" // At 9:00 and 18:00 UTC+3
-	moonPhaseDailyCron    = "30 17 * * *"  // At 20:30 UTC+3
-	holidayDailyCron      = "2 6 * * *"    // At 9:02 UTC+3
+	telegramUpdatesModePolling = "polling"
+	telegramUpdatesModeWebhook = "webhook"
+)
+
+// Sources of moon phases
+const (
+	moonPhaseProviderFarmSense = "farmsense" // FarmSense API, computed locally when it fails
+	moonPhaseProviderLocal     = "local"
 )
 
 // Pool intervals for loaders
 const (
 	weatherPoolInterval      = 30 * time.Minute
+	forecastPoolInterval     = 3 * time.Hour
 	exchangeRatePoolInterval = 8 * time.Hour
 	moonPhasePoolInterval    = 30 * time.Minute
+	newsWatchPoolInterval    = 15 * time.Minute
 )
 
-// Locations for a weather forecast
-var weatherForecastLocations = []domain.Location{
-	domain.SaintPetersburg,
-	domain.Antalya,
-	domain.NhaTrang,
-}
-
-// Currency pairs for exchange rate calculations
+// Currency pairs of the exchange rate broadcast and /rate without arguments
 var exchangeRatePairs = []domain.CurrencyPair{
-	{domain.USD, domain.RUB},
+	{Base: domain.USD, Quote: domain.RUB},
 }
 
 type Config struct {
@@ -68,6 +88,13 @@ type Config struct {
 	PgURL                     string  `env:"DATABASE_URL"`
 	PgHost                    string  `env:"DB_HOST" envDefault:"localhost:65433"`
 	Port                      string  `env:"PORT" envDefault:"8080"`
+	TelegramUpdatesMode       string  `env:"TELEGRAM_UPDATES_MODE" envDefault:"polling"`
+	TelegramWebhookURL        string  `env:"TELEGRAM_WEBHOOK_URL"`
+	TelegramWebhookSecret     string  `env:"TELEGRAM_WEBHOOK_SECRET"`
+	WeatherProvider           string  `env:"WEATHER_PROVIDER" envDefault:"openweathermap"`
+	WeatherFallbackProvider   string  `env:"WEATHER_FALLBACK_PROVIDER" envDefault:"openmeteo"`
+	MoonPhaseProvider         string  `env:"MOON_PHASE_PROVIDER" envDefault:"farmsense"`
+	HolidaysImportToken       string  `env:"HOLIDAYS_IMPORT_TOKEN"`
 }
 
 func main() {
@@ -117,10 +144,39 @@ func setupWorkers() (workers.Group, error) {
 		return nil, fmt.Errorf("creating db: %v", err)
 	}
 
-	telegramClient, err := telegram.NewClient(cfg.TelegramBotToken)
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	var webhook *telegram.WebhookConfig
+	switch cfg.TelegramUpdatesMode {
+	case telegramUpdatesModePolling:
+	case telegramUpdatesModeWebhook:
+		webhook = &telegram.WebhookConfig{
+			URL:         cfg.TelegramWebhookURL,
+			SecretToken: cfg.TelegramWebhookSecret,
+		}
+	default:
+		return nil, fmt.Errorf("unknown telegram updates mode %q", cfg.TelegramUpdatesMode)
+	}
+
+	telegramClient, err := telegram.NewClient(cfg.TelegramBotToken, webhook)
 	if err != nil {
 		return nil, fmt.Errorf("creating telegram bot: %v", err)
 	}
+
+	if webhook != nil {
+		webhookURL, err := url.Parse(webhook.URL)
+		if err != nil {
+			return nil, fmt.Errorf("parsing telegram webhook url: %v", err)
+		}
+		path := webhookURL.Path
+		if path == "" {
+			path = "/"
+		}
+		mux.Handle("POST "+path, telegramClient.WebhookHandler())
+	}
 	authenticator := auth.NewAuthenticator(cfg.TelegramAuthorizedUserIDs)
 
 	/*openAIClient, err := openai.NewClient(cfg.OpenAIToken)
@@ -134,33 +190,79 @@ func setupWorkers() (workers.Group, error) {
 	}
 
 	weatherRepo := repository.NewWeatherRepository(db)
-	weatherReportGenerator := report.NewWeather(weatherForecastLocations, weatherRepo, &formatter.Weather{})
+	locationRepo := repository.NewLocationRepository(db)
+	weatherReportGenerator := report.NewWeather(locationRepo, weatherRepo, &formatter.Weather{})
+	geocodingClient := openweathermap.NewGeocodingClient(cfg.OpenWeatherMapAPIKey)
+	forecastRepo := repository.NewForecastRepository(db)
+	weatherAlertRepo := repository.NewWeatherAlertRepository(db)
+	weatherSummaryFormatter := formatter.WeatherSummary{}
+	forecastReportGenerator := report.NewForecast(locationRepo, forecastRepo, &formatter.Forecast{})
 
 	exchangeRateRepo := repository.NewExchangeRateRepository(db)
 	exchangeRateFormatter := formatter.ExchangeRate{}
 	exchangeRatePlotReportGenerator := report.NewExchangeRatePlot(exchangeRateRepo, &exchangeRateFormatter)
+	alertRepo := repository.NewAlertRepository(db)
 
 	moonPhaseRepo := repository.NewMoonPhaseRepository(db)
-	moonPhaseReportGenerator := report.NewMoonPhase(moonPhaseRepo, &formatter.MoonPhase{})
+	moonPhaseReportGenerator := report.NewMoonPhase(
+		moonPhaseRepo,
+		&formatter.MoonPhase{},
+		googleAIClient,
+		repository.NewMoonAdviceRepository(db),
+	)
+	moonCalculator := astronomy.NewMoonCalculator()
 
 	chatRepository := repository.NewChatRepository(db)
+	subscriptionRepository := repository.NewSubscriptionRepository(db)
 
 	holidayRepository := repository.NewHolidayRepository(db)
-	holidayReportGenerator := report.NewHoliday(holidayRepository)
+	chatEventRepo := repository.NewChatEventRepository(db)
+	holidayCategoryRepo := repository.NewHolidayCategoryRepository(db)
+	holidayReportGenerator := report.NewHoliday(holidayRepository, chatEventRepo, holidayCategoryRepo)
+	// The import writes to the database, it is not served without a token as the mux is public
+	if cfg.HolidaysImportToken != "" {
+		holidayImportHandler, err := api.NewHolidayImportHandler(parser.HolidayParser{}, holidayRepository, cfg.HolidaysImportToken)
+		if err != nil {
+			return nil, fmt.Errorf("creating holiday import handler: %v", err)
+		}
+		mux.Handle("POST /api/holidays/import", holidayImportHandler)
+	} else {
+		slog.Info("holiday import is disabled, HOLIDAYS_IMPORT_TOKEN is not set")
+	}
 
 	hackerNewsService := service.NewsHackerNewsService()
+	newsWatchRepo := repository.NewNewsWatchRepository(db)
 
 	messagesCh := make(chan domain.Message)
 	commands := []telegram.Command{
 		command.NewGetHackerNews(hackerNewsService, googleAIClient, telegramClient),
+		command.NewNewsWatch(newsWatchRepo, messagesCh),
 		command.NewRegister(chatRepository, messagesCh),
-		command.NewWeather(weatherReportGenerator, messagesCh),
+		command.NewSubscribe(subscriptionRepository, messagesCh),
+		command.NewUnsubscribe(subscriptionRepository, messagesCh),
+		command.NewSettings(chatRepository, subscriptionRepository, messagesCh),
+		command.NewWeather(weatherReportGenerator, locationRepo, geocodingClient, messagesCh),
+		command.NewForecast(forecastReportGenerator, messagesCh),
+		command.NewSun(report.NewSun(locationRepo, weatherRepo, &formatter.Sun{}), messagesCh),
+		command.NewWeatherChart(report.NewWeatherPlot(locationRepo, weatherRepo, &weatherSummaryFormatter), messagesCh),
+		command.NewWeatherAlert(weatherAlertRepo, messagesCh),
 		command.NewExchangeRate(exchangeRatePlotReportGenerator, exchangeRatePairs, messagesCh),
-		command.NewMoonPhase(moonPhaseReportGenerator, messagesCh),
-		command.NewHoliday(holidayReportGenerator, messagesCh),
+		command.NewAlert(alertRepo, exchangeRateRepo, messagesCh),
+		command.NewAlerts(alertRepo, messagesCh),
+		command.NewConvert(exchangeRateRepo, exchangeRatePoolInterval, messagesCh),
+		command.NewMoonPhase(moonPhaseReportGenerator, report.NewMoonCalendar(chatRepository, moonCalculator, &formatter.MoonPhase{}), chatRepository, messagesCh),
+		command.NewHoliday(holidayReportGenerator, holidayCategoryRepo, chatRepository, messagesCh),
+		command.NewEvent(chatEventRepo, chatRepository, messagesCh),
 	}
 
-	commandDispatcher := telegram.NewCommandDispatcher(commands)
+	commandDispatcher, err := telegram.NewCommandDispatcher(telegramClient.BotName(), commands, messagesCh)
+	if err != nil {
+		return nil, fmt.Errorf("creating command dispatcher: %v", err)
+	}
+
+	if err := telegramClient.SetCommands(commandDispatcher.BotCommands()); err != nil {
+		slog.Warn("publishing bot commands", logger.Err(err))
+	}
 
 	if worker, err = telegramservice.NewService(telegramClient, authenticator, commandDispatcher, messagesCh); err == nil {
 		workerGroup = append(workerGroup, worker)
@@ -168,14 +270,36 @@ func setupWorkers() (workers.Group, error) {
 		return nil, err
 	}
 
-	openWeatherClient := openweathermap.NewClient(cfg.OpenWeatherMapAPIKey)
+	if worker, err = httpserver.NewService(cfg.Port, mux); err == nil {
+		workerGroup = append(workerGroup, worker)
+	} else {
+		return nil, err
+	}
+
+	weatherProvider, err := newWeatherProvider(cfg)
+	if err != nil {
+		return nil, err
+	}
 
 	if worker, err = loader.NewService[*domain.Weather, domain.Location](
 		"weather loader",
-		weatherForecastLocations,
-		openWeatherClient,
+		locationRepo,
+		weatherProvider,
 		weatherRepo,
 		weatherPoolInterval,
+		service.NewWeatherAlertService(weatherAlertRepo, weatherRepo, messagesCh),
+	); err == nil {
+		workerGroup = append(workerGroup, worker)
+	} else {
+		return nil, err
+	}
+
+	if worker, err = loader.NewService[*domain.Forecast, domain.Location](
+		"forecast loader",
+		locationRepo,
+		openweathermap.NewForecastClient(cfg.OpenWeatherMapAPIKey),
+		forecastRepo,
+		forecastPoolInterval,
 	); err == nil {
 		workerGroup = append(workerGroup, worker)
 	} else {
@@ -184,9 +308,19 @@ func setupWorkers() (workers.Group, error) {
 
 	if worker, err = workers.NewBroadcaster(
 		"weather broadcaster",
-		weatherDailyCron,
-		chatRepository,
-		weatherReportGenerator,
+		workers.NewScheduler(domain.TopicWeather, weatherDeliveryTimes, subscriptionRepository),
+		report.NewMorningWeather(chatRepository, weatherReportGenerator, forecastReportGenerator),
+		messagesCh,
+	); err == nil {
+		workerGroup = append(workerGroup, worker)
+	} else {
+		return nil, err
+	}
+
+	if worker, err = workers.NewBroadcaster(
+		"weekly weather broadcaster",
+		workers.NewScheduler(domain.TopicWeeklyWeather, weeklyWeatherDeliveryTimes, subscriptionRepository).Weekly(weeklyWeatherDeliveryDay),
+		report.NewWeeklyWeather(locationRepo, weatherRepo, &weatherSummaryFormatter),
 		messagesCh,
 	); err == nil {
 		workerGroup = append(workerGroup, worker)
@@ -196,12 +330,12 @@ func setupWorkers() (workers.Group, error) {
 
 	openExchangeRatesClient := openexchangerates.NewClient(cfg.OpenExchangeRatesAPPID)
 
-	if worker, err = loader.NewService[*domain.ExchangeRate, domain.CurrencyPair](
+	if worker, err = loader.NewFetcherService[*domain.ExchangeRateSnapshot](
 		"exchange rate loader",
-		exchangeRatePairs,
 		openExchangeRatesClient,
 		exchangeRateRepo,
 		exchangeRatePoolInterval,
+		service.NewExchangeRateAlertService(alertRepo, messagesCh),
 	); err == nil {
 		workerGroup = append(workerGroup, worker)
 	} else {
@@ -210,8 +344,7 @@ func setupWorkers() (workers.Group, error) {
 
 	if worker, err = plotbroadcaster.NewService(
 		"exchange rate broadcaster",
-		exchangeRateDailyCron,
-		chatRepository,
+		workers.NewScheduler(domain.TopicExchangeRate, exchangeRateDeliveryTimes, subscriptionRepository),
 		exchangeRatePlotReportGenerator,
 		messagesCh,
 		exchangeRatePairs,
@@ -221,12 +354,19 @@ func setupWorkers() (workers.Group, error) {
 		return nil, err
 	}
 
-	farmSenseClient := farmsense.NewClient()
+	var moonPhaseFetcher loader.Fetcher[*domain.MoonPhase]
+	switch cfg.MoonPhaseProvider {
+	case moonPhaseProviderFarmSense:
+		moonPhaseFetcher = loader.NewFallbackFetcher[*domain.MoonPhase](farmsense.NewClient(), moonCalculator, 0)
+	case moonPhaseProviderLocal:
+		moonPhaseFetcher = moonCalculator
+	default:
+		return nil, fmt.Errorf("unknown moon phase provider %q", cfg.MoonPhaseProvider)
+	}
 
-	if worker, err = loader.NewService[*domain.MoonPhase, struct{}](
+	if worker, err = loader.NewFetcherService[*domain.MoonPhase](
 		"moon phase loader",
-		nil,
-		farmSenseClient,
+		moonPhaseFetcher,
 		moonPhaseRepo,
 		moonPhasePoolInterval,
 	); err == nil {
@@ -237,8 +377,7 @@ func setupWorkers() (workers.Group, error) {
 
 	if worker, err = workers.NewBroadcaster(
 		"moon phase broadcaster",
-		moonPhaseDailyCron,
-		chatRepository,
+		workers.NewScheduler(domain.TopicMoonPhase, moonPhaseDeliveryTimes, subscriptionRepository),
 		moonPhaseReportGenerator,
 		messagesCh,
 	); err == nil {
@@ -249,8 +388,7 @@ func setupWorkers() (workers.Group, error) {
 
 	if worker, err = workers.NewBroadcaster(
 		"holiday broadcaster",
-		holidayDailyCron,
-		chatRepository,
+		workers.NewScheduler(domain.TopicHoliday, holidayDeliveryTimes, subscriptionRepository),
 		holidayReportGenerator,
 		messagesCh,
 	); err == nil {
@@ -259,5 +397,51 @@ func setupWorkers() (workers.Group, error) {
 		return nil, err
 	}
 
+	if worker, err = workers.NewMarkdownBroadcaster(
+		"news digest broadcaster",
+		workers.NewScheduler(domain.TopicNews, newsDeliveryTimes, subscriptionRepository),
+		report.NewNewsDigest(hackerNewsService, googleAIClient, repository.NewNewsRepository(db)),
+		telegramClient,
+	); err == nil {
+		workerGroup = append(workerGroup, worker)
+	} else {
+		return nil, err
+	}
+
+	if worker, err = loader.NewFetcherService[[]domain.NewsItem](
+		"hacker news watch loader",
+		hackerNewsService,
+		service.NewNewsWatchService(newsWatchRepo, messagesCh),
+		newsWatchPoolInterval,
+	); err == nil {
+		workerGroup = append(workerGroup, worker)
+	} else {
+		return nil, err
+	}
+
 	return workerGroup, nil
 }
+
+// newWeatherProvider creates the configured weather provider, wrapped with the fallback one if it is set
+func newWeatherProvider(cfg Config) (weatherprovider.Provider, error) {
+	providers := map[string]func() weatherprovider.Provider{
+		weatherprovider.OpenWeatherMap: func() weatherprovider.Provider { return openweathermap.NewClient(cfg.OpenWeatherMapAPIKey) },
+		weatherprovider.OpenMeteo:      func() weatherprovider.Provider { return openmeteo.NewClient() },
+	}
+
+	newPrimary, ok := providers[cfg.WeatherProvider]
+	if !ok {
+		return nil, fmt.Errorf("unknown weather provider %q", cfg.WeatherProvider)
+	}
+	if cfg.WeatherFallbackProvider == "" || cfg.WeatherFallbackProvider == cfg.WeatherProvider {
+		return newPrimary(), nil
+	}
+
+	newSecondary, ok := providers[cfg.WeatherFallbackProvider]
+	if !ok {
+		return nil, fmt.Errorf("unknown weather fallback provider %q", cfg.WeatherFallbackProvider)
+	}
+
+	// The primary provider is retried on the next pass
+	return loader.NewFallback[*domain.Weather, domain.Location](newPrimary(), newSecondary(), weatherPoolInterval/2), nil
+}
@@ -0,0 +1,116 @@
+package api
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/sushkevichd/day-guide-telegram-bot/pkg/domain"
+	"github.com/sushkevichd/day-guide-telegram-bot/pkg/logger"
+)
+
+const maxHolidayPageSize = 10 << 20
+
+// Layouts of the day of a page, given by the date form field or by the file name
+var holidayDateLayouts = []string{time.DateOnly, "02.01.2006", "01-02", "02.01"}
+
+type HolidayParser interface {
+	Parse(r io.Reader) ([]domain.Holiday, error)
+}
+
+type HolidayImporter interface {
+	Import(ctx context.Context, date time.Time, holidays []domain.Holiday) (*domain.HolidayImportSummary, error)
+}
+
+type holidayImport struct {
+	parser   HolidayParser
+	importer HolidayImporter
+	token    string
+}
+
+// NewHolidayImportHandler accepts the day pages posted by tools/import_holidays as the multipart "file" field. The
+// requests must be authorized with the token as a bearer token, it is required since the handler writes to the database.
+func NewHolidayImportHandler(parser HolidayParser, importer HolidayImporter, token string) (http.Handler, error) {
+	if token == "" {
+		return nil, errors.New("empty holiday import token")
+	}
+
+	return &holidayImport{
+		parser:   parser,
+		importer: importer,
+		token:    token,
+	}, nil
+}
+
+type holidayImportResponse struct {
+	File string `json:"file"`
+	Date string `json:"date"`
+	*domain.HolidayImportSummary
+}
+
+func (h *holidayImport) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(h.token)) != 1 {
+		http.Error(w, "invalid token", http.StatusUnauthorized)
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxHolidayPageSize)
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		http.Error(w, fmt.Sprintf("reading file: %v", err), http.StatusBadRequest)
+		return
+	}
+	defer file.Close()
+
+	dateValue := r.FormValue("date")
+	if dateValue == "" {
+		dateValue = strings.TrimSuffix(header.Filename, path.Ext(header.Filename))
+	}
+	date, err := parseHolidayDate(dateValue)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	holidays, err := h.parser.Parse(file)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("parsing %s: %v", header.Filename, err), http.StatusUnprocessableEntity)
+		return
+	}
+
+	summary, err := h.importer.Import(r.Context(), date, holidays)
+	if err != nil {
+		slog.Error("failed to import holidays", "file", header.Filename, logger.Err(err))
+		http.Error(w, fmt.Sprintf("importing %s: %v", header.Filename, err), http.StatusInternalServerError)
+		return
+	}
+
+	slog.Info("imported holidays", "file", header.Filename, "date", date.Format("01-02"), "summary", *summary)
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(holidayImportResponse{
+		File:                 header.Filename,
+		Date:                 date.Format("01-02"),
+		HolidayImportSummary: summary,
+	}); err != nil {
+		slog.Warn("failed to write response", logger.Err(err))
+	}
+}
+
+func parseHolidayDate(s string) (time.Time, error) {
+	for _, layout := range holidayDateLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unknown date %q, expected one of %s", s, strings.Join(holidayDateLayouts, ", "))
+}
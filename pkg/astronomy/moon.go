@@ -0,0 +1,243 @@
+package astronomy
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/sushkevichd/day-guide-telegram-bot/pkg/domain"
+)
+
+// The positions of the Sun and the Moon are computed with the low precision formulas from "Astronomical Algorithms" by
+// J. Meeus as used by the SunCalc library, the phase times are accurate to a few hours
+
+const (
+	rad = math.Pi / 180
+
+	julianUnixEpoch = 2440587.5 // Julian date of 1970-01-01T00:00Z
+	julianJ2000     = 2451545.0 // Julian date of 2000-01-01T12:00Z
+
+	earthObliquity = rad * 23.4397
+
+	// SynodicMonth is the mean time between two new moons in days
+	SynodicMonth = 29.530588853
+
+	astronomicalUnitKm = 149597870.7
+)
+
+type moonCalculator struct {
+	now func() time.Time
+}
+
+// NewMoonCalculator creates a moon phase fetcher computing the phase locally, without any API
+func NewMoonCalculator() *moonCalculator {
+	return &moonCalculator{now: time.Now}
+}
+
+func (c *moonCalculator) FetchData(_ context.Context) (*domain.MoonPhase, error) {
+	phase := MoonPhaseAt(c.now())
+	return &phase, nil
+}
+
+// MoonPhaseAt computes the moon phase at the given moment
+func MoonPhaseAt(t time.Time) domain.MoonPhase {
+	d := daysSinceJ2000(t)
+	fraction, phase, moonDistance := moonIllumination(d)
+	age := phase * SynodicMonth
+
+	return domain.MoonPhase{
+		Age:             int(age) + 1, // lunar days are counted from 1
+		Names:           []string{traditionalMoonName(t.Month())},
+		Phase:           phaseName(age),
+		DistanceToEarth: math.Round(moonDistance),
+		IlluminationPrc: int(fraction * 100),
+		DistanceToSun:   math.Round(sunDistance(d)),
+	}
+}
+
+// MoonCycleAt returns the position of the moment in the synodic month: 0 is the new moon, 0.25 the first quarter, 0.5
+// the full moon and 0.75 the last quarter
+func MoonCycleAt(t time.Time) float64 {
+	_, phase, _ := moonIllumination(daysSinceJ2000(t))
+	return phase
+}
+
+func daysSinceJ2000(t time.Time) float64 {
+	return float64(t.UnixMilli())/float64(24*time.Hour/time.Millisecond) + julianUnixEpoch - julianJ2000
+}
+
+// moonIllumination returns the illuminated fraction of the Moon, the position in the synodic month and the distance
+// to the Moon in km
+func moonIllumination(d float64) (fraction, phase, distance float64) {
+	sunRA, sunDec := sunCoords(d)
+	moonRA, moonDec, moonDist := moonCoords(d)
+
+	const sunDist = 149598000 // km
+
+	phi := math.Acos(math.Sin(sunDec)*math.Sin(moonDec) + math.Cos(sunDec)*math.Cos(moonDec)*math.Cos(sunRA-moonRA))
+	inc := math.Atan2(sunDist*math.Sin(phi), moonDist-sunDist*math.Cos(phi))
+	angle := math.Atan2(
+		math.Cos(sunDec)*math.Sin(sunRA-moonRA),
+		math.Sin(sunDec)*math.Cos(moonDec)-math.Cos(sunDec)*math.Sin(moonDec)*math.Cos(sunRA-moonRA),
+	)
+
+	sign := 1.0
+	if angle < 0 {
+		sign = -1
+	}
+
+	fraction = (1 + math.Cos(inc)) / 2
+	phase = 0.5 + 0.5*inc*sign/math.Pi
+
+	return fraction, phase, moonDist
+}
+
+func rightAscension(l, b float64) float64 {
+	return math.Atan2(math.Sin(l)*math.Cos(earthObliquity)-math.Tan(b)*math.Sin(earthObliquity), math.Cos(l))
+}
+
+func declination(l, b float64) float64 {
+	return math.Asin(math.Sin(b)*math.Cos(earthObliquity) + math.Cos(b)*math.Sin(earthObliquity)*math.Sin(l))
+}
+
+func solarMeanAnomaly(d float64) float64 {
+	return rad * (357.5291 + 0.98560028*d)
+}
+
+func sunCoords(d float64) (ra, dec float64) {
+	m := solarMeanAnomaly(d)
+	center := rad * (1.9148*math.Sin(m) + 0.02*math.Sin(2*m) + 0.0003*math.Sin(3*m))
+	perihelion := rad * 102.9372
+	l := m + center + perihelion + math.Pi
+
+	return rightAscension(l, 0), declination(l, 0)
+}
+
+// sunDistance returns the distance to the Sun in km
+func sunDistance(d float64) float64 {
+	m := solarMeanAnomaly(d)
+	return (1.00014 - 0.01671*math.Cos(m) - 0.00014*math.Cos(2*m)) * astronomicalUnitKm
+}
+
+func moonCoords(d float64) (ra, dec, dist float64) {
+	l := rad * (218.316 + 13.176396*d) // ecliptic longitude
+	m := rad * (134.963 + 13.064993*d) // mean anomaly
+	f := rad * (93.272 + 13.229350*d)  // mean distance
+
+	lon := l + rad*6.289*math.Sin(m)
+	lat := rad * 5.128 * math.Sin(f)
+	dist = 385001 - 20905*math.Cos(m)
+
+	return rightAscension(lon, lat), declination(lon, lat), dist
+}
+
+// phaseName names the phase by the moon age in days the same way as the FarmSense API
+func phaseName(age float64) string {
+	switch {
+	case age < 1:
+		return "New Moon"
+	case age < 6.38:
+		return "Waxing Crescent"
+	case age < 8.38:
+		return "1st Quarter"
+	case age < 13.77:
+		return "Waxing Gibbous"
+	case age < 15.77:
+		return "Full Moon"
+	case age < 21.15:
+		return "Waning Gibbous"
+	case age < 23.15:
+		return "3rd Quarter"
+	case age < 28.53:
+		return "Waning Crescent"
+	default:
+		return "Dark Moon"
+	}
+}
+
+func traditionalMoonName(month time.Month) string {
+	return [...]string{
+		time.January:   "Wolf Moon",
+		time.February:  "Snow Moon",
+		time.March:     "Worm Moon",
+		time.April:     "Pink Moon",
+		time.May:       "Flower Moon",
+		time.June:      "Strawberry Moon",
+		time.July:      "Buck Moon",
+		time.August:    "Sturgeon Moon",
+		time.September: "Harvest Moon",
+		time.October:   "Hunter's Moon",
+		time.November:  "Beaver Moon",
+		time.December:  "Cold Moon",
+	}[month]
+}
+
+// Principal phases in the order of the synodic month, named as in the FarmSense API
+var principalPhases = []struct {
+	name  string
+	cycle float64
+}{
+	{"New Moon", 0},
+	{"1st Quarter", 0.25},
+	{"Full Moon", 0.5},
+	{"3rd Quarter", 0.75},
+}
+
+const (
+	phaseSearchStep      = 6 * time.Hour
+	phaseSearchPrecision = time.Minute
+)
+
+// PhaseAt computes the moon phase at the given moment
+func (c *moonCalculator) PhaseAt(t time.Time) domain.MoonPhase {
+	return MoonPhaseAt(t)
+}
+
+// NextPhases returns the moments of the next new moon, first quarter, full moon and last quarter after from, sorted by
+// time
+func (c *moonCalculator) NextPhases(from time.Time) []domain.MoonPhaseEvent {
+	var events []domain.MoonPhaseEvent
+	found := map[string]bool{}
+
+	prev := from
+	for len(events) < len(principalPhases) {
+		next := prev.Add(phaseSearchStep)
+		for _, p := range principalPhases {
+			if found[p.name] || !crosses(p.cycle, prev, next) {
+				continue
+			}
+			found[p.name] = true
+			events = append(events, domain.MoonPhaseEvent{
+				Phase: p.name,
+				Time:  bisectPhase(p.cycle, prev, next),
+			})
+		}
+		prev = next
+	}
+
+	return events
+}
+
+// cycleOffset is the signed distance of the moment from the target position in the synodic month, in (-0.5, 0.5]
+func cycleOffset(target float64, t time.Time) float64 {
+	offset := MoonCycleAt(t) - target
+	return offset - math.Floor(offset+0.5)
+}
+
+// crosses reports whether the Moon passes the target position between from and to. The offset jumps from 0.5 to -0.5
+// at the opposite position, so only a rise through zero counts.
+func crosses(target float64, from, to time.Time) bool {
+	return cycleOffset(target, from) < 0 && cycleOffset(target, to) >= 0
+}
+
+func bisectPhase(target float64, from, to time.Time) time.Time {
+	for to.Sub(from) > phaseSearchPrecision {
+		mid := from.Add(to.Sub(from) / 2)
+		if cycleOffset(target, mid) < 0 {
+			from = mid
+		} else {
+			to = mid
+		}
+	}
+	return to.Truncate(phaseSearchPrecision)
+}
@@ -0,0 +1,15 @@
+-- +migrate Up
+CREATE TABLE chat_subscriptions (
+    chat_id BIGINT REFERENCES chats(id) ON DELETE CASCADE,
+    topic TEXT NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (chat_id, topic)
+);
+
+CREATE INDEX idx_chat_subscriptions_topic ON chat_subscriptions (topic);
+
+-- Chats registered before subscriptions existed keep receiving every daily report
+INSERT INTO chat_subscriptions (chat_id, topic)
+SELECT c.id, t.topic
+FROM chats c
+CROSS JOIN (VALUES ('weather'), ('rate'), ('moon'), ('holiday')) AS t(topic);
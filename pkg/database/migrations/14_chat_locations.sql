@@ -0,0 +1,20 @@
+-- +migrate Up
+CREATE TABLE chat_locations (
+    id SERIAL UNIQUE,
+    chat_id BIGINT REFERENCES chats(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    lat FLOAT NOT NULL,
+    lon FLOAT NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (chat_id, name)
+);
+
+-- Existing chats keep the locations that used to be hard-coded for everyone
+INSERT INTO chat_locations (chat_id, name, lat, lon)
+SELECT c.id, l.name, l.lat, l.lon
+FROM chats c
+CROSS JOIN (VALUES
+    ('Санкт-Петербург', 59.9387, 30.3162),
+    ('Анталья', 36.8865, 30.7030),
+    ('Нячанг', 12.2450, 109.1943)
+) AS l(name, lat, lon);
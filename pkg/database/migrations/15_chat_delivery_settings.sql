@@ -0,0 +1,4 @@
+-- +migrate Up
+ALTER TABLE chats ADD COLUMN timezone TEXT NOT NULL DEFAULT 'Europe/Moscow';
+
+ALTER TABLE chat_subscriptions ADD COLUMN delivery_times TEXT[] NOT NULL DEFAULT '{}';
@@ -0,0 +1,33 @@
+-- +migrate Up
+-- created_at keeps the time zone, /convert compares it with the bot's clock to warn about outdated rates
+CREATE TABLE exchange_rate_snapshots (
+    id SERIAL PRIMARY KEY,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    base TEXT NOT NULL,
+    rates JSONB NOT NULL
+);
+
+CREATE INDEX idx_exchange_rate_snapshots_created_at ON exchange_rate_snapshots (created_at);
+
+-- Keep the history polled before snapshots existed. A loader pass saved its pairs one by one, rows less than a minute
+-- apart belong to one pass. The pairs of a pass become one snapshot per base currency, the cross rates are computed
+-- within a snapshot, so the rates of every pair stay as they were polled. The USD snapshot of a pass is inserted last
+-- to be the latest one.
+INSERT INTO exchange_rate_snapshots (created_at, base, rates)
+SELECT min(created_at), base, jsonb_object_agg(quote, rate) || jsonb_build_object(base, 1)
+FROM (
+    SELECT created_at, base, quote, rate, sum(new_pass) OVER (ORDER BY created_at, id) AS pass
+    FROM (
+        SELECT id, created_at, base, quote, rate,
+               CASE
+                   WHEN created_at - lag(created_at) OVER (ORDER BY created_at, id) <= INTERVAL '1 minute' THEN 0
+                   ELSE 1
+               END AS new_pass
+        FROM exchange_rates
+        WHERE created_at IS NOT NULL AND base IS NOT NULL AND quote IS NOT NULL AND rate IS NOT NULL
+    ) AS polled
+) AS passes
+GROUP BY pass, base
+ORDER BY min(created_at), base = 'USD';
+
+DROP TABLE exchange_rates;
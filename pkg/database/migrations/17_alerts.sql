@@ -0,0 +1,14 @@
+-- +migrate Up
+CREATE TABLE alerts (
+    id BIGSERIAL PRIMARY KEY,
+    chat_id BIGINT NOT NULL REFERENCES chats(id) ON DELETE CASCADE,
+    base TEXT NOT NULL,
+    quote TEXT NOT NULL,
+    condition TEXT NOT NULL,
+    threshold DOUBLE PRECISION NOT NULL,
+    triggered BOOLEAN NOT NULL DEFAULT FALSE,
+    reference_rate DOUBLE PRECISION NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_alerts_chat_id ON alerts (chat_id);
@@ -0,0 +1,20 @@
+-- +migrate Up
+-- Forecasts are stored by the coordinates they were loaded for, different places may share a name
+CREATE TABLE forecasts (
+    location TEXT NOT NULL,
+    lat FLOAT NOT NULL,
+    lon FLOAT NOT NULL,
+    date DATE NOT NULL,
+    timezone_offset INTEGER NOT NULL,
+    temp_min FLOAT NOT NULL,
+    temp_max FLOAT NOT NULL,
+    precipitation_probability INTEGER NOT NULL,
+    wind_speed FLOAT NOT NULL,
+    weather TEXT NOT NULL,
+    weather_verbose TEXT NOT NULL,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (lat, lon, date)
+);
+
+-- Chats opt in to the forecast for the day instead of the current weather in the morning report
+ALTER TABLE chats ADD COLUMN weather_forecast BOOLEAN NOT NULL DEFAULT FALSE;
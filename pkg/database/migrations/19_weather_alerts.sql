@@ -0,0 +1,18 @@
+-- +migrate Up
+CREATE TABLE weather_alert_rules (
+    chat_id BIGINT REFERENCES chats(id) ON DELETE CASCADE,
+    kind TEXT NOT NULL,
+    threshold FLOAT NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (chat_id, kind)
+);
+
+CREATE TABLE weather_alert_states (
+    chat_id BIGINT,
+    kind TEXT,
+    location TEXT NOT NULL,
+    active BOOLEAN NOT NULL DEFAULT FALSE,
+    fired_at TIMESTAMPTZ,
+    PRIMARY KEY (chat_id, kind, location),
+    FOREIGN KEY (chat_id, kind) REFERENCES weather_alert_rules(chat_id, kind) ON DELETE CASCADE
+);
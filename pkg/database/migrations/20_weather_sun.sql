@@ -0,0 +1,16 @@
+-- +migrate Up
+ALTER TABLE weather ADD COLUMN sunrise TIMESTAMPTZ;
+ALTER TABLE weather ADD COLUMN sunset TIMESTAMPTZ;
+ALTER TABLE weather ADD COLUMN timezone_offset INTEGER NOT NULL DEFAULT 0;
+
+-- The weather is stored with the coordinates it was loaded for, different places may share a name. The saved weather
+-- was loaded for the first location added under the name.
+ALTER TABLE weather ADD COLUMN lat FLOAT;
+ALTER TABLE weather ADD COLUMN lon FLOAT;
+
+UPDATE weather w
+SET lat = l.lat, lon = l.lon
+FROM (SELECT DISTINCT ON (name) name, lat, lon FROM chat_locations ORDER BY name, created_at) AS l
+WHERE w.location = l.name;
+
+CREATE INDEX idx_weather_coordinates_created_at ON weather (lat, lon, created_at);
@@ -0,0 +1,8 @@
+-- +migrate Up
+CREATE TABLE moon_advices (
+    date DATE NOT NULL,
+    age INTEGER NOT NULL,
+    advice TEXT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (date, age)
+);
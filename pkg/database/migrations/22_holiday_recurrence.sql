@@ -0,0 +1,97 @@
+-- +migrate Up
+ALTER TABLE holidays ADD COLUMN rule TEXT NOT NULL DEFAULT 'fixed';
+ALTER TABLE holidays ADD COLUMN month INT;
+ALTER TABLE holidays ADD COLUMN day INT;
+ALTER TABLE holidays ADD COLUMN weekday INT;    -- 0 is Sunday
+ALTER TABLE holidays ADD COLUMN week INT;       -- -1 is the last week of the month
+ALTER TABLE holidays ADD COLUMN day_offset INT; -- days after the date of an easter or nth_weekday rule
+
+UPDATE holidays SET month = EXTRACT(MONTH FROM date), day = EXTRACT(DAY FROM date);
+
+-- Holidays celebrated on a weekday of the month, seeded by their 2024 dates. The ones with an offset follow a weekday,
+-- e.g. the Black Friday is a day after the 4th Thursday of November, their month is the one of the weekday.
+CREATE TEMPORARY TABLE holiday_weekday_rules (name TEXT, weekday INT, week INT, day_offset INT) ON COMMIT DROP;
+
+INSERT INTO holiday_weekday_rules (name, weekday, week, day_offset) VALUES
+    ('Международный день кооперативов', 6, 1, 0),
+    ('Международный день Днепра', 6, 1, 0),
+    ('День работников морского и речного флота России', 0, 1, 0),
+    ('День рыбака', 0, 2, 0),
+    ('День российской почты', 0, 2, 0),
+    ('День действий против рыбной ловли в России', 0, 2, 0),
+    ('День металлурга', 0, 3, 0),
+    ('День системного администратора', 5, -1, 0),
+    ('День работника торговли в России', 6, 4, 0),
+    ('День Военно-Морского Флота России', 0, -1, 0),
+    ('Международный день пива', 5, 1, 0),
+    ('День железнодорожника', 0, 1, 0),
+    ('День физкультурника в России', 6, 2, 0),
+    ('День строителя', 0, 2, 0),
+    ('Всемирный день бездомных животных', 6, 3, 0),
+    ('День Воздушного Флота России', 0, 3, 0),
+    ('День шахтера', 0, -1, 0),
+    ('День дальнобойщика в России', 6, -1, 0),
+    ('День ветеринарного работника России', 6, -1, 0),
+    ('День нефтяника', 0, 1, 0),
+    ('Всемирный день бороды', 6, 1, 0),
+    ('День танкиста в России', 0, 2, 0),
+    ('День работников леса', 0, 3, 0),
+    ('Всемирный день донора костного мозга', 6, 3, 0),
+    ('Всемирный день моря', 4, -1, 0),
+    ('День машиностроителя', 0, -1, 0),
+    ('Международный день глухих', 0, -1, 0),
+    ('День тигра на Дальнем Востоке', 0, -1, 0),
+    ('Всемирный день улыбки', 5, 1, 0),
+    ('Всемирный день Хабитат', 1, 1, 0),
+    ('Всемирный день архитектуры', 1, 1, 0),
+    ('Всемирный день зрения', 4, 2, 0),
+    ('Всемирный день яйца', 5, 2, 0),
+    ('День работника сельского хозяйства и перерабатывающей промышленности в России', 0, 2, 0),
+    ('Международный день кредитных союзов', 4, 3, 0),
+    ('День отца в России', 0, 3, 0),
+    ('День работников дорожного хозяйства в России', 0, 3, 0),
+    ('День работников пищевой промышленности России', 0, 3, 0),
+    ('День автомобилиста в России', 0, -1, 0),
+    ('Международный день тёщи', 0, 4, 0),
+    ('Международный день школьных библиотек', 1, 4, 0),
+    ('Всемирный день мужчин', 6, 1, 0),
+    ('Всемирный день качества', 4, 2, 0),
+    ('Всемирный день юзабилити', 4, 2, 0),
+    ('День географических информационных систем (День ГИС)', 3, 3, 0),
+    ('Всемирный день философии', 4, 3, 0),
+    ('День отказа от курения', 4, 3, 0),
+    ('День матери в России', 0, -1, 0),
+    ('Черная пятница', 4, 4, 1),
+    ('Всемирный день отказа от покупок', 4, 4, 1),
+    ('Киберпонедельник', 4, 4, 4);
+
+UPDATE holidays
+SET rule = 'nth_weekday', month = EXTRACT(MONTH FROM holidays.date - r.day_offset), day = NULL, weekday = r.weekday, week = r.week, day_offset = r.day_offset
+FROM holiday_weekday_rules r
+WHERE holidays.name = r.name;
+
+-- The 256th day of the year, September 13 or September 12 in leap years
+UPDATE holidays SET rule = 'day_of_year', month = NULL, day = 256 WHERE name = 'День программиста в России';
+
+-- The dates are dropped only when every holiday got its rule
+-- +migrate StatementBegin
+DO $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM holiday_weekday_rules r WHERE NOT EXISTS (SELECT 1 FROM holidays h WHERE h.name = r.name)) THEN
+        RAISE EXCEPTION 'weekday rules of holidays missing in the table';
+    END IF;
+
+    IF EXISTS (
+        SELECT 1 FROM holidays
+        WHERE (rule = 'fixed' AND (month IS NULL OR day IS NULL))
+           OR (rule = 'nth_weekday' AND (month IS NULL OR weekday IS NULL OR week IS NULL))
+           OR (rule = 'day_of_year' AND day IS NULL)
+    ) THEN
+        RAISE EXCEPTION 'holidays without recurrence';
+    END IF;
+END $$;
+-- +migrate StatementEnd
+
+ALTER TABLE holidays DROP COLUMN date;
+
+CREATE INDEX idx_holidays_month ON holidays (month);
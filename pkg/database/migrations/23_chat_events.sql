@@ -0,0 +1,14 @@
+-- +migrate Up
+CREATE TABLE chat_events (
+    id BIGSERIAL PRIMARY KEY,
+    chat_id BIGINT NOT NULL REFERENCES chats(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    month INT NOT NULL,
+    day INT NOT NULL,
+    year INT,
+    yearly BOOLEAN NOT NULL DEFAULT FALSE,
+    remind_days INT NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_chat_events_chat_id ON chat_events (chat_id);
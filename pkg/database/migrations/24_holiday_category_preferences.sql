@@ -0,0 +1,16 @@
+-- +migrate Up
+-- The categories created by the import later get the generic holiday emoji
+ALTER TABLE holiday_categories ADD COLUMN emoji TEXT NOT NULL DEFAULT '🎉';
+
+UPDATE holiday_categories SET emoji = '🌍' WHERE name = 'Международные праздники';
+UPDATE holiday_categories SET emoji = '🇷🇺' WHERE name = 'Праздники России';
+UPDATE holiday_categories SET emoji = '🪆' WHERE name = 'Праздники славян';
+UPDATE holiday_categories SET emoji = '🤝' WHERE name = 'Праздники ООН';
+UPDATE holiday_categories SET emoji = '✝️' WHERE name = 'Православные праздники';
+
+-- Categories are shown by default, so the ones added later reach every chat
+CREATE TABLE chat_hidden_holiday_categories (
+    chat_id BIGINT NOT NULL REFERENCES chats(id) ON DELETE CASCADE,
+    category_id INT NOT NULL REFERENCES holiday_categories(id) ON DELETE CASCADE,
+    PRIMARY KEY (chat_id, category_id)
+);
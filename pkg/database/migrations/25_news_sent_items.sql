@@ -0,0 +1,8 @@
+-- +migrate Up
+CREATE TABLE news_sent_items (
+    chat_id BIGINT NOT NULL REFERENCES chats(id) ON DELETE CASCADE,
+    item_id TEXT NOT NULL,
+    score INT NOT NULL,
+    sent_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (chat_id, item_id)
+);
@@ -0,0 +1,18 @@
+-- +migrate Up
+CREATE TABLE news_watches (
+    id BIGSERIAL PRIMARY KEY,
+    chat_id BIGINT NOT NULL REFERENCES chats(id) ON DELETE CASCADE,
+    keywords TEXT[] NOT NULL,
+    threshold INT NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_news_watches_chat_id ON news_watches (chat_id);
+
+-- A story is reported to a chat once even if it matches several watches
+CREATE TABLE news_watch_sent_items (
+    chat_id BIGINT NOT NULL REFERENCES chats(id) ON DELETE CASCADE,
+    item_id TEXT NOT NULL,
+    sent_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (chat_id, item_id)
+);
@@ -0,0 +1,13 @@
+package domain
+
+import tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+// CallbackAnswer stops the loading indicator of a pressed inline button, optionally showing a notification
+type CallbackAnswer struct {
+	CallbackQueryID string
+	Text            string
+}
+
+func (c *CallbackAnswer) ToChatMessage() tgbotapi.Chattable {
+	return tgbotapi.NewCallback(c.CallbackQueryID, c.Text)
+}
@@ -2,8 +2,14 @@ package domain
 
 import "time"
 
+const DefaultTimezone = "Europe/Moscow"
+
 type Chat struct {
 	ID           int64
 	RegisteredBy string
 	RegisteredAt time.Time
+	Timezone     string
+
+	// WeatherForecast replaces the current weather with the forecast for the day in the morning report
+	WeatherForecast bool
 }
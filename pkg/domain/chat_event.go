@@ -0,0 +1,63 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// ChatEvent is a date kept by a chat, e.g. a birthday or a team anniversary
+type ChatEvent struct {
+	ID     int64
+	ChatID int64
+	Name   string
+	Month  time.Month
+	Day    int
+	Year   int // of a one-time event, of the first occurrence of a yearly one when known, 0 otherwise
+	Yearly bool
+
+	// RemindDays is how many days ahead the event is announced with a countdown, 0 to mention it only on the day
+	RemindDays int
+}
+
+// NextDate returns the first date of the event on or after the day of from, false for a past one-time event
+func (e ChatEvent) NextDate(from time.Time) (time.Time, bool) {
+	if e.Yearly {
+		return HolidayRecurrence{Rule: HolidayRuleFixed, Month: e.Month, Day: e.Day}.NextDate(from)
+	}
+
+	date := time.Date(e.Year, e.Month, e.Day, 0, 0, 0, 0, time.UTC)
+	return date, !date.Before(time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.UTC))
+}
+
+// DaysLeft returns the number of days from the day of today to the next date of the event
+func (e ChatEvent) DaysLeft(today time.Time) (int, bool) {
+	date, ok := e.NextDate(today)
+	if !ok {
+		return 0, false
+	}
+	day := time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, time.UTC)
+	return int(date.Sub(day).Hours() / 24), true
+}
+
+// Anniversary returns the number of years the yearly event is celebrated on the date, 0 when the first year is unknown
+func (e ChatEvent) Anniversary(date time.Time) int {
+	if !e.Yearly || e.Year == 0 {
+		return 0
+	}
+	return date.Year() - e.Year
+}
+
+func (e ChatEvent) String() string {
+	s := fmt.Sprintf("%02d.%02d", e.Day, e.Month)
+	if e.Year != 0 {
+		s += fmt.Sprintf(".%d", e.Year)
+	}
+	s += " " + e.Name
+	if e.Yearly {
+		s += ", yearly"
+	}
+	if e.RemindDays > 0 {
+		s += fmt.Sprintf(", reminder %d days ahead", e.RemindDays)
+	}
+	return s
+}
@@ -1,5 +1,10 @@
 package domain
 
+import (
+	"fmt"
+	"strings"
+)
+
 type Currency string
 
 const (
@@ -8,6 +13,20 @@ const (
 	TRY Currency = "TRY"
 )
 
+// ParseCurrency accepts an ISO 4217 code in any case
+func ParseCurrency(s string) (Currency, error) {
+	code := strings.ToUpper(strings.TrimSpace(s))
+	if len(code) != 3 {
+		return "", fmt.Errorf("invalid currency code %q", s)
+	}
+	for _, r := range code {
+		if r < 'A' || r > 'Z' {
+			return "", fmt.Errorf("invalid currency code %q", s)
+		}
+	}
+	return Currency(code), nil
+}
+
 func (c Currency) String() string {
 	return string(c)
 }
@@ -16,3 +35,7 @@ type CurrencyPair struct {
 	Base  Currency
 	Quote Currency
 }
+
+func (p CurrencyPair) String() string {
+	return fmt.Sprintf("%s/%s", p.Base, p.Quote)
+}
@@ -0,0 +1,44 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DeliveryTime is a wall clock time of a daily broadcast in the chat's timezone
+type DeliveryTime struct {
+	Hour   int
+	Minute int
+}
+
+func ParseDeliveryTime(s string) (DeliveryTime, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(s))
+	if err != nil {
+		return DeliveryTime{}, fmt.Errorf("invalid time %q, expected HH:MM", s)
+	}
+	return DeliveryTime{Hour: t.Hour(), Minute: t.Minute()}, nil
+}
+
+// ParseDeliveryTimes parses a comma separated list of times, e.g. "09:00,18:00"
+func ParseDeliveryTimes(s string) ([]DeliveryTime, error) {
+	var times []DeliveryTime
+	for _, part := range strings.Split(s, ",") {
+		t, err := ParseDeliveryTime(part)
+		if err != nil {
+			return nil, err
+		}
+		times = append(times, t)
+	}
+	return times, nil
+}
+
+func (d DeliveryTime) String() string {
+	return fmt.Sprintf("%02d:%02d", d.Hour, d.Minute)
+}
+
+// On returns the instant of the delivery time on the given day in loc.
+// Times skipped by a DST transition are normalized by time.Date.
+func (d DeliveryTime) On(year int, month time.Month, day int, loc *time.Location) time.Time {
+	return time.Date(year, month, day, d.Hour, d.Minute, 0, 0, loc)
+}
@@ -0,0 +1,47 @@
+package domain
+
+import tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+// EditTextMessage replaces the text and the inline keyboard of a sent message
+type EditTextMessage struct {
+	ChatID      int64
+	MessageID   int
+	Content     string
+	ReplyMarkup *tgbotapi.InlineKeyboardMarkup
+}
+
+func (e *EditTextMessage) ToChatMessage() tgbotapi.Chattable {
+	msg := tgbotapi.NewEditMessageText(e.ChatID, e.MessageID, e.Content)
+	msg.ParseMode = tgbotapi.ModeMarkdown
+	msg.ReplyMarkup = e.ReplyMarkup
+	return msg
+}
+
+// EditImageMessage replaces the photo, the caption and the inline keyboard of a sent message
+type EditImageMessage struct {
+	ChatID      int64
+	MessageID   int
+	Content     []byte
+	Caption     string
+	ReplyMarkup *tgbotapi.InlineKeyboardMarkup
+}
+
+func (e *EditImageMessage) ToChatMessage() tgbotapi.Chattable {
+	photo := tgbotapi.NewInputMediaPhoto(tgbotapi.FileBytes{
+		Name:  "image.png",
+		Bytes: e.Content,
+	})
+	if e.Caption != "" {
+		photo.Caption = e.Caption
+		photo.ParseMode = tgbotapi.ModeMarkdown
+	}
+
+	return tgbotapi.EditMessageMediaConfig{
+		BaseEdit: tgbotapi.BaseEdit{
+			ChatID:      e.ChatID,
+			MessageID:   e.MessageID,
+			ReplyMarkup: e.ReplyMarkup,
+		},
+		Media: photo,
+	}
+}
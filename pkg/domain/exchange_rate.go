@@ -2,6 +2,9 @@ package domain
 
 import "time"
 
+// DefaultExchangeRatePeriod is the number of days shown on an exchange rate chart by default
+const DefaultExchangeRatePeriod = 30
+
 type ExchangeRate struct {
 	Timestamp time.Time
 	Pair      CurrencyPair
@@ -0,0 +1,98 @@
+package domain
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+type AlertCondition string
+
+const (
+	AlertAbove  AlertCondition = ">"
+	AlertBelow  AlertCondition = "<"
+	AlertChange AlertCondition = "change"
+)
+
+// ExchangeRateAlert notifies a chat when the rate of a pair crosses Threshold, or moves by Threshold percent for
+// AlertChange
+type ExchangeRateAlert struct {
+	ID        int64
+	ChatID    int64
+	Pair      CurrencyPair
+	Condition AlertCondition
+	Threshold float64
+
+	// Triggered is set while the rate stays past the threshold, so that an alert fires once per crossing
+	Triggered bool
+	// ReferenceRate is the rate a change is measured from, reset every time a change alert fires
+	ReferenceRate float64
+}
+
+// ParseAlertCondition parses "> 100", "< 90" and "change 2%"
+func ParseAlertCondition(args []string) (AlertCondition, float64, error) {
+	if len(args) != 2 {
+		return "", 0, fmt.Errorf("expected condition and threshold")
+	}
+
+	condition := AlertCondition(strings.ToLower(args[0]))
+	switch condition {
+	case AlertAbove, AlertBelow, AlertChange:
+	default:
+		return "", 0, fmt.Errorf("unknown condition %q", args[0])
+	}
+
+	value := strings.Replace(args[1], ",", ".", 1)
+	if condition == AlertChange {
+		value = strings.TrimSuffix(value, "%")
+	}
+
+	threshold, err := strconv.ParseFloat(value, 64)
+	if err != nil || threshold <= 0 || math.IsInf(threshold, 0) {
+		return "", 0, fmt.Errorf("invalid threshold %q", args[1])
+	}
+
+	return condition, threshold, nil
+}
+
+// Evaluate updates the alert state with the new rate and reports whether the chat has to be notified
+func (a *ExchangeRateAlert) Evaluate(rate float64) bool {
+	switch a.Condition {
+	case AlertAbove, AlertBelow:
+		crossed := rate > a.Threshold
+		if a.Condition == AlertBelow {
+			crossed = rate < a.Threshold
+		}
+		fire := crossed && !a.Triggered
+		a.Triggered = crossed
+		return fire
+	case AlertChange:
+		if a.ReferenceRate == 0 {
+			a.ReferenceRate = rate
+			return false
+		}
+		if math.Abs(a.ChangePercent(rate)) < a.Threshold {
+			return false
+		}
+		a.ReferenceRate = rate
+		return true
+	default:
+		return false
+	}
+}
+
+// ChangePercent is the change of the rate relative to the reference rate
+func (a *ExchangeRateAlert) ChangePercent(rate float64) float64 {
+	if a.ReferenceRate == 0 {
+		return 0
+	}
+	return (rate - a.ReferenceRate) / a.ReferenceRate * 100
+}
+
+func (a *ExchangeRateAlert) String() string {
+	if a.Condition == AlertChange {
+		return fmt.Sprintf("%s change %g%%", a.Pair, a.Threshold)
+	}
+	return fmt.Sprintf("%s %s %g", a.Pair, a.Condition, a.Threshold)
+}
@@ -0,0 +1,45 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+var ErrUnknownCurrency = errors.New("unknown currency")
+
+// ExchangeRateSnapshot holds the rates of all currencies against the base currency at one moment
+type ExchangeRateSnapshot struct {
+	Timestamp time.Time
+	Base      Currency
+	Rates     map[string]float64 // units of the currency per one unit of Base
+}
+
+// Rate computes the rate of any pair, crossing through the base currency when neither side is the base
+func (s *ExchangeRateSnapshot) Rate(pair CurrencyPair) (*ExchangeRate, error) {
+	base, err := s.rate(pair.Base)
+	if err != nil {
+		return nil, err
+	}
+	quote, err := s.rate(pair.Quote)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ExchangeRate{
+		Timestamp: s.Timestamp,
+		Pair:      pair,
+		Rate:      quote / base,
+	}, nil
+}
+
+func (s *ExchangeRateSnapshot) rate(c Currency) (float64, error) {
+	if c == s.Base {
+		return 1, nil
+	}
+	rate, ok := s.Rates[c.String()]
+	if !ok || rate == 0 {
+		return 0, fmt.Errorf("%w: %s", ErrUnknownCurrency, c)
+	}
+	return rate, nil
+}
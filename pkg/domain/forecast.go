@@ -0,0 +1,25 @@
+package domain
+
+import "time"
+
+// MaxForecastDays is the number of days covered by the 5 day / 3 hour forecast
+const MaxForecastDays = 5
+
+type Forecast struct {
+	Location       string
+	Lat            float64 // of the location the forecast was loaded for
+	Lon            float64 // of the location the forecast was loaded for
+	TimezoneOffset int     // seconds east of UTC of the location
+	Days           []ForecastDay
+}
+
+// ForecastDay summarizes the forecast of one local day of the location
+type ForecastDay struct {
+	Date                     time.Time // midnight of the local date, in UTC
+	TempMin                  float64   // Celsius
+	TempMax                  float64   // Celsius
+	PrecipitationProbability int       // %
+	WindSpeed                float64   // maximum, meter/sec
+	Weather                  string    // for icon
+	WeatherVerbose           string
+}
@@ -1,6 +1,9 @@
 package domain
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+)
 
 type NewsItem struct {
 	Rank     int
@@ -20,3 +23,27 @@ func (n NewsItem) ToText() string {
 		n.Rank, n.Title, n.Site, n.Score, n.Author, n.Age, n.Comments,
 	)
 }
+
+// NewsList is a Hacker News story list
+type NewsList string
+
+const (
+	NewsTop  NewsList = "top"
+	NewsBest NewsList = "best"
+	NewsNew  NewsList = "new"
+	NewsAsk  NewsList = "ask"
+	NewsShow NewsList = "show"
+)
+
+func NewsLists() []NewsList {
+	return []NewsList{NewsTop, NewsBest, NewsNew, NewsAsk, NewsShow}
+}
+
+func ParseNewsList(s string) (NewsList, error) {
+	for _, l := range NewsLists() {
+		if strings.EqualFold(s, string(l)) {
+			return l, nil
+		}
+	}
+	return "", fmt.Errorf("unknown news list %q", s)
+}
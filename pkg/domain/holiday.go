@@ -5,6 +5,121 @@ import "time"
 type Holiday struct {
 	OrderNumber int
 	Name        string
-	Date        time.Time
-	Categories  []string
+	Date        time.Time // the date the holiday is celebrated on, resolved from the recurrence
+	Recurrence  HolidayRecurrence
+	Categories  []HolidayCategory
+}
+
+type HolidayCategory struct {
+	ID    int64
+	Name  string
+	Emoji string
+}
+
+// Visible reports whether a chat hiding the categories sees the holiday, it is hidden only when all its categories are
+func (h Holiday) Visible(hidden map[int64]bool) bool {
+	if len(h.Categories) == 0 {
+		return true
+	}
+	for _, c := range h.Categories {
+		if !hidden[c.ID] {
+			return true
+		}
+	}
+	return false
+}
+
+type HolidayRule string
+
+const (
+	HolidayRuleFixed      HolidayRule = "fixed"       // Day of Month
+	HolidayRuleNthWeekday HolidayRule = "nth_weekday" // DayOffset days after the Week-th Weekday of Month, the last one when Week is -1
+	HolidayRuleEaster     HolidayRule = "easter"      // DayOffset days after the Orthodox Easter
+	HolidayRuleLastDay    HolidayRule = "last_day"    // the last day of Month
+	HolidayRuleDayOfYear  HolidayRule = "day_of_year" // Day-th day of the year, e.g. 256 for the Programmer's Day
+)
+
+// LastWeek is the Week of a HolidayRuleNthWeekday recurrence for the last weekday of the month
+const LastWeek = -1
+
+// HolidayRecurrence describes on which date the holiday is celebrated in any year, only the fields of the Rule are set
+type HolidayRecurrence struct {
+	Rule      HolidayRule
+	Month     time.Month
+	Day       int
+	Weekday   time.Weekday
+	Week      int
+	DayOffset int
+}
+
+// DateIn returns the date of the holiday in the year, false when it is not celebrated that year, e.g. February 29
+func (r HolidayRecurrence) DateIn(year int) (time.Time, bool) {
+	switch r.Rule {
+	case HolidayRuleFixed:
+		d := time.Date(year, r.Month, r.Day, 0, 0, 0, 0, time.UTC)
+		return d, d.Month() == r.Month
+	case HolidayRuleNthWeekday:
+		d, ok := nthWeekday(year, r.Month, r.Weekday, r.Week)
+		return d.AddDate(0, 0, r.DayOffset), ok
+	case HolidayRuleEaster:
+		return OrthodoxEaster(year).AddDate(0, 0, r.DayOffset), true
+	case HolidayRuleLastDay:
+		return time.Date(year, r.Month+1, 0, 0, 0, 0, 0, time.UTC), true
+	case HolidayRuleDayOfYear:
+		return time.Date(year, time.January, r.Day, 0, 0, 0, 0, time.UTC), true
+	default:
+		return time.Time{}, false
+	}
+}
+
+// Matches reports whether the holiday is celebrated on the date
+func (r HolidayRecurrence) Matches(date time.Time) bool {
+	d, ok := r.DateIn(date.Year())
+	return ok && d.Month() == date.Month() && d.Day() == date.Day()
+}
+
+// NextDate returns the first date of the holiday on or after the day of from, false when it is not celebrated within
+// the next years
+func (r HolidayRecurrence) NextDate(from time.Time) (time.Time, bool) {
+	day := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.UTC)
+	// February 29 recurs in 4 years at most
+	for year := from.Year(); year <= from.Year()+4; year++ {
+		if d, ok := r.DateIn(year); ok && !d.Before(day) {
+			return d, true
+		}
+	}
+	return time.Time{}, false
+}
+
+func nthWeekday(year int, month time.Month, weekday time.Weekday, week int) (time.Time, bool) {
+	if week == LastWeek {
+		last := time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC)
+		return last.AddDate(0, 0, -(int(last.Weekday()-weekday)+7)%7), true
+	}
+
+	first := time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+	d := first.AddDate(0, 0, (int(weekday-first.Weekday())+7)%7+7*(week-1))
+	return d, week > 0 && d.Month() == month
+}
+
+// OrthodoxEaster returns the date of the Orthodox Easter in the year, in the Gregorian calendar. The Julian date is
+// computed with the Meeus algorithm and shifted by the difference between the calendars, valid for 1900-2099.
+func OrthodoxEaster(year int) time.Time {
+	a := year % 4
+	b := year % 7
+	c := year % 19
+	d := (19*c + 15) % 30
+	e := (2*a + 4*b - d + 34) % 7
+	month := (d + e + 114) / 31
+	day := (d+e+114)%31 + 1
+
+	return time.Date(year, time.Month(month), day+13, 0, 0, 0, 0, time.UTC)
+}
+
+// HolidayImportSummary counts the rows changed by the import of the holidays of a day
+type HolidayImportSummary struct {
+	HolidaysInserted   int `json:"holidays_inserted"`
+	HolidaysUpdated    int `json:"holidays_updated"`
+	CategoriesInserted int `json:"categories_inserted"`
+	LinksInserted      int `json:"links_inserted"`
 }
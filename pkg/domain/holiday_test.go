@@ -0,0 +1,204 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+// The Julian date from the Meeus algorithm is 13 days behind the Gregorian
+// one in 1900-2099, so a missing shift moves every case below by two weeks.
+func TestOrthodoxEaster(t *testing.T) {
+	tests := []struct {
+		year int
+		want string
+	}{
+		{2000, "2000-04-30"},
+		{2010, "2010-04-04"}, // the same day as the Western Easter
+		{2021, "2021-05-02"},
+		{2023, "2023-04-16"},
+		{2024, "2024-05-05"},
+		{2025, "2025-04-20"},
+		{2026, "2026-04-12"},
+	}
+
+	for _, tt := range tests {
+		if got := OrthodoxEaster(tt.year).Format(time.DateOnly); got != tt.want {
+			t.Errorf("OrthodoxEaster(%d) = %s, want %s", tt.year, got, tt.want)
+		}
+	}
+}
+
+func TestHolidayRecurrenceDateIn(t *testing.T) {
+	tests := []struct {
+		name       string
+		recurrence HolidayRecurrence
+		year       int
+		want       string // empty when the holiday is not celebrated that year
+	}{
+		{
+			name:       "fixed",
+			recurrence: HolidayRecurrence{Rule: HolidayRuleFixed, Month: time.March, Day: 8},
+			year:       2025,
+			want:       "2025-03-08",
+		},
+		{
+			name:       "February 29 in a leap year",
+			recurrence: HolidayRecurrence{Rule: HolidayRuleFixed, Month: time.February, Day: 29},
+			year:       2024,
+			want:       "2024-02-29",
+		},
+		{
+			name:       "February 29 in a common year",
+			recurrence: HolidayRecurrence{Rule: HolidayRuleFixed, Month: time.February, Day: 29},
+			year:       2025,
+		},
+		{
+			name:       "second Sunday",
+			recurrence: HolidayRecurrence{Rule: HolidayRuleNthWeekday, Month: time.May, Weekday: time.Sunday, Week: 2},
+			year:       2025,
+			want:       "2025-05-11",
+		},
+		{
+			name:       "fifth weekday missing in the month",
+			recurrence: HolidayRecurrence{Rule: HolidayRuleNthWeekday, Month: time.February, Weekday: time.Monday, Week: 5},
+			year:       2025,
+		},
+		{
+			name:       "last Friday, sysadmin day",
+			recurrence: HolidayRecurrence{Rule: HolidayRuleNthWeekday, Month: time.July, Weekday: time.Friday, Week: LastWeek},
+			year:       2025,
+			want:       "2025-07-25",
+		},
+		{
+			name:       "last Friday of a month ending on Wednesday",
+			recurrence: HolidayRecurrence{Rule: HolidayRuleNthWeekday, Month: time.July, Weekday: time.Friday, Week: LastWeek},
+			year:       2024,
+			want:       "2024-07-26",
+		},
+		{
+			name:       "last Sunday of a month ending on Sunday",
+			recurrence: HolidayRecurrence{Rule: HolidayRuleNthWeekday, Month: time.November, Weekday: time.Sunday, Week: LastWeek},
+			year:       2025,
+			want:       "2025-11-30",
+		},
+		{
+			name:       "day after the fourth Thursday, Black Friday",
+			recurrence: HolidayRecurrence{Rule: HolidayRuleNthWeekday, Month: time.November, Weekday: time.Thursday, Week: 4, DayOffset: 1},
+			year:       2025,
+			want:       "2025-11-28",
+		},
+		{
+			name:       "offset into the next month, Cyber Monday",
+			recurrence: HolidayRecurrence{Rule: HolidayRuleNthWeekday, Month: time.November, Weekday: time.Thursday, Week: 4, DayOffset: 4},
+			year:       2024,
+			want:       "2024-12-02",
+		},
+		{
+			name:       "offset onto the first of the next month",
+			recurrence: HolidayRecurrence{Rule: HolidayRuleNthWeekday, Month: time.November, Weekday: time.Thursday, Week: 4, DayOffset: 4},
+			year:       2025,
+			want:       "2025-12-01",
+		},
+		{
+			name:       "Easter",
+			recurrence: HolidayRecurrence{Rule: HolidayRuleEaster},
+			year:       2026,
+			want:       "2026-04-12",
+		},
+		{
+			name:       "after Easter, Trinity",
+			recurrence: HolidayRecurrence{Rule: HolidayRuleEaster, DayOffset: 49},
+			year:       2025,
+			want:       "2025-06-08",
+		},
+		{
+			name:       "before Easter, Palm Sunday",
+			recurrence: HolidayRecurrence{Rule: HolidayRuleEaster, DayOffset: -7},
+			year:       2024,
+			want:       "2024-04-28",
+		},
+		{
+			name:       "last day of February in a leap year",
+			recurrence: HolidayRecurrence{Rule: HolidayRuleLastDay, Month: time.February},
+			year:       2024,
+			want:       "2024-02-29",
+		},
+		{
+			name:       "last day of December",
+			recurrence: HolidayRecurrence{Rule: HolidayRuleLastDay, Month: time.December},
+			year:       2025,
+			want:       "2025-12-31",
+		},
+		{
+			name:       "day 256 in a common year",
+			recurrence: HolidayRecurrence{Rule: HolidayRuleDayOfYear, Day: 256},
+			year:       2025,
+			want:       "2025-09-13",
+		},
+		{
+			name:       "day 256 in a leap year",
+			recurrence: HolidayRecurrence{Rule: HolidayRuleDayOfYear, Day: 256},
+			year:       2024,
+			want:       "2024-09-12",
+		},
+		{
+			name:       "unknown rule",
+			recurrence: HolidayRecurrence{Rule: "lunar"},
+			year:       2025,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := tt.recurrence.DateIn(tt.year)
+			switch {
+			case tt.want == "" && ok:
+				t.Errorf("DateIn(%d) = %s, want none", tt.year, got.Format(time.DateOnly))
+			case tt.want != "" && !ok:
+				t.Errorf("DateIn(%d) = none, want %s", tt.year, tt.want)
+			case ok && got.Format(time.DateOnly) != tt.want:
+				t.Errorf("DateIn(%d) = %s, want %s", tt.year, got.Format(time.DateOnly), tt.want)
+			}
+		})
+	}
+}
+
+func TestHolidayRecurrenceNextDate(t *testing.T) {
+	leapDay := HolidayRecurrence{Rule: HolidayRuleFixed, Month: time.February, Day: 29}
+	newYear := HolidayRecurrence{Rule: HolidayRuleFixed, Month: time.January, Day: 1}
+	blackFriday := HolidayRecurrence{Rule: HolidayRuleNthWeekday, Month: time.November, Weekday: time.Thursday, Week: 4, DayOffset: 1}
+
+	tests := []struct {
+		name       string
+		recurrence HolidayRecurrence
+		from       time.Time
+		want       string
+	}{
+		{"on the day, late in it", newYear, time.Date(2025, 1, 1, 23, 30, 0, 0, time.UTC), "2025-01-01"},
+		{"after the day", newYear, time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC), "2026-01-01"},
+		{"February 29 after a leap year", leapDay, time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), "2028-02-29"},
+		{"February 29 in a leap year", leapDay, time.Date(2028, 1, 15, 0, 0, 0, 0, time.UTC), "2028-02-29"},
+		{"floating later in the year", blackFriday, time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC), "2025-11-28"},
+		{"floating next year", blackFriday, time.Date(2025, 11, 29, 0, 0, 0, 0, time.UTC), "2026-11-27"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := tt.recurrence.NextDate(tt.from)
+			if !ok || got.Format(time.DateOnly) != tt.want {
+				t.Errorf("NextDate(%s) = %s, %v, want %s", tt.from.Format(time.DateTime), got.Format(time.DateOnly), ok, tt.want)
+			}
+		})
+	}
+}
+
+func TestHolidayRecurrenceMatches(t *testing.T) {
+	sysadminDay := HolidayRecurrence{Rule: HolidayRuleNthWeekday, Month: time.July, Weekday: time.Friday, Week: LastWeek}
+
+	if !sysadminDay.Matches(time.Date(2025, 7, 25, 10, 0, 0, 0, time.UTC)) {
+		t.Error("Matches(2025-07-25) = false, want true")
+	}
+	if sysadminDay.Matches(time.Date(2025, 7, 26, 10, 0, 0, 0, time.UTC)) {
+		t.Error("Matches(2025-07-26) = true, want false")
+	}
+}
@@ -8,6 +8,7 @@ type ImageMessage struct {
 	Prompt           string
 	Content          []byte
 	Caption          string
+	ReplyMarkup      *tgbotapi.InlineKeyboardMarkup
 }
 
 func (i *ImageMessage) ToChatMessage() tgbotapi.Chattable {
@@ -21,5 +22,7 @@ func (i *ImageMessage) ToChatMessage() tgbotapi.Chattable {
 		msg.ParseMode = tgbotapi.ModeMarkdown
 	}
 
+	msg.ReplyMarkup = i.ReplyMarkup
+
 	return msg
 }
@@ -1,11 +1,14 @@
 package domain
 
-type Location string
+import "fmt"
 
-const (
-	SaintPetersburg Location = "Санкт-Петербург"
-	Pitkyaranta     Location = "Питкяранта"
-	Antalya         Location = "Анталья"
-	Phuket          Location = "Пхукет"
-	NhaTrang        Location = "Нячанг"
-)
+type Location struct {
+	ID   int64 // of a chat's location, zero for a geocoded one
+	Name string
+	Lat  float64
+	Lon  float64
+}
+
+func (l Location) String() string {
+	return fmt.Sprintf("%s (%.4f, %.4f)", l.Name, l.Lat, l.Lon)
+}
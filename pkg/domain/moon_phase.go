@@ -1,5 +1,7 @@
 package domain
 
+import "time"
+
 type MoonPhase struct {
 	Age             int
 	Names           []string
@@ -8,3 +10,9 @@ type MoonPhase struct {
 	IlluminationPrc int
 	DistanceToSun   float64
 }
+
+// MoonPhaseEvent is the moment of a principal moon phase: new moon, first quarter, full moon or last quarter
+type MoonPhaseEvent struct {
+	Phase string
+	Time  time.Time
+}
@@ -0,0 +1,63 @@
+package domain
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// DefaultNewsWatchThreshold is the score a story has to reach to be reported when a watch is added without one
+const DefaultNewsWatchThreshold = 100
+
+// NewsWatch notifies a chat about the Hacker News stories which title has any of the keywords once their score reaches
+// Threshold
+type NewsWatch struct {
+	ID        int64
+	ChatID    int64
+	Keywords  []string // lower case
+	Threshold int
+}
+
+// Matches reports whether the story is to be reported, keywords are matched as whole words ignoring case
+func (w NewsWatch) Matches(item NewsItem) bool {
+	return item.Score >= w.Threshold && len(w.MatchedKeywords(item)) > 0
+}
+
+// MatchedKeywords returns the keywords in the title. A keyword is split into words like the title, so "node.js" or
+// "open-source" match the same words following each other in it.
+func (w NewsWatch) MatchedKeywords(item NewsItem) []string {
+	words := newsWords(item.Title)
+
+	var matched []string
+	for _, k := range w.Keywords {
+		if containsWords(words, newsWords(k)) {
+			matched = append(matched, k)
+		}
+	}
+	return matched
+}
+
+func newsWords(s string) []string {
+	return strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		// "C++" and "C#" are words too
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '+' && r != '#'
+	})
+}
+
+// containsWords reports whether seq is found in words as a whole
+func containsWords(words, seq []string) bool {
+	if len(seq) == 0 {
+		return false
+	}
+	for i := 0; i+len(seq) <= len(words); i++ {
+		if slices.Equal(words[i:i+len(seq)], seq) {
+			return true
+		}
+	}
+	return false
+}
+
+func (w NewsWatch) String() string {
+	return fmt.Sprintf("%s, score %d+", strings.Join(w.Keywords, " "), w.Threshold)
+}
@@ -0,0 +1,10 @@
+package domain
+
+import "time"
+
+type Subscription struct {
+	ChatID        int64
+	Topic         Topic
+	Location      *time.Location
+	DeliveryTimes []DeliveryTime // empty means the topic defaults
+}
@@ -8,10 +8,12 @@ type TextMessage struct {
 	ChatID           int64
 	ReplyToMessageID int
 	Content          string
+	ReplyMarkup      *tgbotapi.InlineKeyboardMarkup
 }
 
 func (t *TextMessage) ToChatMessage() tgbotapi.Chattable {
 	msg := tgbotapi.NewMessage(t.ChatID, t.Content)
 	msg.ParseMode = tgbotapi.ModeMarkdown
+	msg.ReplyMarkup = t.ReplyMarkup
 	return msg
 }
@@ -0,0 +1,34 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+)
+
+type Topic string
+
+const (
+	TopicWeather       Topic = "weather"
+	TopicExchangeRate  Topic = "rate"
+	TopicMoonPhase     Topic = "moon"
+	TopicHoliday       Topic = "holiday"
+	TopicNews          Topic = "news"
+	TopicWeeklyWeather Topic = "weekly" // weekly weather summary
+)
+
+func Topics() []Topic {
+	return []Topic{TopicWeather, TopicExchangeRate, TopicMoonPhase, TopicHoliday, TopicNews, TopicWeeklyWeather}
+}
+
+func ParseTopic(s string) (Topic, error) {
+	for _, t := range Topics() {
+		if strings.EqualFold(s, string(t)) {
+			return t, nil
+		}
+	}
+	return "", fmt.Errorf("unknown topic %q", s)
+}
+
+func (t Topic) String() string {
+	return string(t)
+}
@@ -1,7 +1,12 @@
 package domain
 
+import "time"
+
 type Weather struct {
+	Timestamp      time.Time // when the weather was saved, zero for fresh data
 	Location       string
+	Lat            float64 // of the location the weather was loaded for, zero for the saved weather
+	Lon            float64 // of the location the weather was loaded for, zero for the saved weather
 	Temp           float64 // Celsius
 	TempFeel       float64 // Celsius
 	Pressure       int     // hPa
@@ -10,4 +15,83 @@ type Weather struct {
 	WeatherVerbose string
 	WindSpeed      float64 // meter/sec
 	WindDirection  string
+	Sunrise        time.Time // zero when unknown
+	Sunset         time.Time // zero when unknown
+	TimezoneOffset int       // seconds east of UTC of the location
+}
+
+// DayLength is the time between sunrise and sunset, zero when they are unknown
+func (w Weather) DayLength() time.Duration {
+	if w.Sunrise.IsZero() || w.Sunset.IsZero() {
+		return 0
+	}
+	return w.Sunset.Sub(w.Sunrise)
+}
+
+// WeatherSummary aggregates the weather of a location over a period
+type WeatherSummary struct {
+	Location    string
+	TempMin     float64 // Celsius
+	TempMax     float64 // Celsius
+	TempAvg     float64 // Celsius
+	PressureMin int     // hPa
+	PressureMax int     // hPa
+}
+
+// SummarizeWeather aggregates the weather history of one location, it returns nil for empty history
+func SummarizeWeather(history []Weather) *WeatherSummary {
+	if len(history) == 0 {
+		return nil
+	}
+
+	s := WeatherSummary{
+		Location:    history[0].Location,
+		TempMin:     history[0].Temp,
+		TempMax:     history[0].Temp,
+		PressureMin: history[0].Pressure,
+		PressureMax: history[0].Pressure,
+	}
+
+	var tempSum float64
+	for _, w := range history {
+		s.TempMin = min(s.TempMin, w.Temp)
+		s.TempMax = max(s.TempMax, w.Temp)
+		s.PressureMin = min(s.PressureMin, w.Pressure)
+		s.PressureMax = max(s.PressureMax, w.Pressure)
+		tempSum += w.Temp
+	}
+	s.TempAvg = tempSum / float64(len(history))
+
+	return &s
+}
+
+// LocalTime converts t to the timezone of the location
+func (w Weather) LocalTime(t time.Time) time.Time {
+	return t.In(time.FixedZone("", w.TimezoneOffset))
+}
+
+// WindDirection names the direction the wind blows from, given in meteorological degrees
+func WindDirection(d int) string {
+	if d == 0 {
+		return "-"
+	}
+
+	switch {
+	case 24 <= d && d <= 68:
+		return "северо-восточный"
+	case 69 <= d && d <= 113:
+		return "восточный"
+	case 114 <= d && d <= 158:
+		return "юго-восточный"
+	case 159 <= d && d <= 203:
+		return "южный"
+	case 204 <= d && d <= 248:
+		return "юго-западный"
+	case 249 <= d && d <= 293:
+		return "западный"
+	case 294 <= d && d <= 338:
+		return "северо-западный"
+	default:
+		return "северный"
+	}
 }
@@ -0,0 +1,53 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+type WeatherAlertKind string
+
+const (
+	WeatherAlertTempDrop WeatherAlertKind = "temp"     // drop versus yesterday, Celsius
+	WeatherAlertWind     WeatherAlertKind = "wind"     // speed above threshold, meter/sec
+	WeatherAlertStorm    WeatherAlertKind = "storm"    // thunderstorm or snow, no threshold
+	WeatherAlertPressure WeatherAlertKind = "pressure" // change versus yesterday in any direction, mm of mercury
+)
+
+func WeatherAlertKinds() []WeatherAlertKind {
+	return []WeatherAlertKind{WeatherAlertTempDrop, WeatherAlertWind, WeatherAlertStorm, WeatherAlertPressure}
+}
+
+func ParseWeatherAlertKind(s string) (WeatherAlertKind, error) {
+	for _, k := range WeatherAlertKinds() {
+		if strings.EqualFold(s, string(k)) {
+			return k, nil
+		}
+	}
+	return "", fmt.Errorf("unknown weather alert %q", s)
+}
+
+// HasThreshold reports whether the rule of the kind is configured with a number
+func (k WeatherAlertKind) HasThreshold() bool {
+	return k != WeatherAlertStorm
+}
+
+func (k WeatherAlertKind) String() string {
+	return string(k)
+}
+
+// WeatherAlertRule is a weather alert configured for all locations of a chat
+type WeatherAlertRule struct {
+	ChatID    int64
+	Kind      WeatherAlertKind
+	Threshold float64
+}
+
+// WeatherAlertState de-duplicates the alerts of a rule for one location
+type WeatherAlertState struct {
+	Rule     WeatherAlertRule
+	Location string
+	Active   bool      // the condition held on the previous evaluation
+	FiredAt  time.Time // zero if the alert has never fired
+}
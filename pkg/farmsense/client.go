@@ -46,12 +46,17 @@ func (c *client) FetchData(ctx context.Context) (*domain.MoonPhase, error) {
 	if err != nil {
 		return nil, fmt.Errorf("executing request: %v", err)
 	}
+	defer resp.Body.Close()
 
 	var res []moonPhasesResponse
 	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
 		return nil, fmt.Errorf("decoding response body: %v", err)
 	}
 
+	if len(res) == 0 {
+		return nil, fmt.Errorf("empty response")
+	}
+
 	if res[0].Error != 0 {
 		return nil, fmt.Errorf("API error: %s", res[0].ErrorMsg)
 	}
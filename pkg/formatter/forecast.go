@@ -0,0 +1,33 @@
+package formatter
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sushkevichd/day-guide-telegram-bot/pkg/domain"
+)
+
+var weekdays = map[time.Weekday]string{
+	time.Monday:    "Пн",
+	time.Tuesday:   "Вт",
+	time.Wednesday: "Ср",
+	time.Thursday:  "Чт",
+	time.Friday:    "Пт",
+	time.Saturday:  "Сб",
+	time.Sunday:    "Вс",
+}
+
+type Forecast struct{}
+
+func (_ *Forecast) Format(f domain.Forecast) string {
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("📅 %s\n", f.Location))
+	for _, d := range f.Days {
+		sb.WriteString(fmt.Sprintf("%s %s %s - %s\n", weatherEmoji(d.Weather), weekdays[d.Date.Weekday()], d.Date.Format("02.01"), d.WeatherVerbose))
+		sb.WriteString(fmt.Sprintf("🌡️ *%.0f…%.0f°C*, ☔ *%d%%*, 🌀 до *%.1fм/c*\n", d.TempMin, d.TempMax, d.PrecipitationProbability, d.WindSpeed))
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,11 @@
+package formatter
+
+import "strings"
+
+var markdownReplacer = strings.NewReplacer("*", "", "_", "", "`", "", "[", "(", "]", ")")
+
+// StripMarkdown drops the Telegram Markdown markers from user or generated text shown in a Markdown message, unbalanced
+// ones would make Telegram reject it. Escaping isn't used as it doesn't work inside a link or bold text.
+func StripMarkdown(s string) string {
+	return markdownReplacer.Replace(s)
+}
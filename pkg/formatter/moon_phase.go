@@ -29,6 +29,22 @@ func (_ *MoonPhase) Format(m domain.MoonPhase) string {
 	return sb.String()
 }
 
+// FormatAdvice drops the markup from the generated text, unbalanced markers would make Telegram reject the message
+func (_ *MoonPhase) FormatAdvice(advice string) string {
+	return fmt.Sprintf("\n%s\n", StripMarkdown(advice))
+}
+
+func (_ *MoonPhase) FormatEvents(events []domain.MoonPhaseEvent) string {
+	var sb strings.Builder
+
+	sb.WriteString("🗓️ Ближайшие фазы Луны\n")
+	for _, e := range events {
+		sb.WriteString(fmt.Sprintf("%s %s - *%s %s*\n", phaseEmoji[e.Phase], moonPhaseDescription(e.Phase), weekdays[e.Time.Weekday()], e.Time.Format("02.01 15:04")))
+	}
+
+	return sb.String()
+}
+
 func moonPhaseDescription(phase string) string {
 	switch {
 	case phase == "New Moon":
@@ -0,0 +1,40 @@
+package formatter
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sushkevichd/day-guide-telegram-bot/pkg/domain"
+)
+
+type Sun struct{}
+
+// Format formats the sunrise, sunset and day length of the weather, weekAgo is nil when there is no history
+func (_ *Sun) Format(w domain.Weather, weekAgo *domain.Weather) string {
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("🌅 %s\n", w.Location))
+	if w.DayLength() == 0 {
+		sb.WriteString("Нет данных о восходе и закате\n")
+		return sb.String()
+	}
+
+	sb.WriteString(fmt.Sprintf("Восход *%s*, закат *%s*\n", w.LocalTime(w.Sunrise).Format("15:04"), w.LocalTime(w.Sunset).Format("15:04")))
+	sb.WriteString(fmt.Sprintf("Световой день *%s*", FormatDuration(w.DayLength())))
+
+	if weekAgo != nil && weekAgo.DayLength() > 0 {
+		change := (w.DayLength() - weekAgo.DayLength()).Round(time.Minute)
+		switch {
+		case change > 0:
+			sb.WriteString(fmt.Sprintf(" (+%dм за неделю)", int(change.Minutes())))
+		case change < 0:
+			sb.WriteString(fmt.Sprintf(" (−%dм за неделю)", int(-change.Minutes())))
+		default:
+			sb.WriteString(" (не изменился за неделю)")
+		}
+	}
+	sb.WriteString("\n")
+
+	return sb.String()
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"math"
 	"strings"
+	"time"
 
 	"github.com/sushkevichd/day-guide-telegram-bot/pkg/domain"
 )
@@ -18,6 +19,9 @@ func (_ *Weather) Format(w domain.Weather) string {
 	sb.WriteString(fmt.Sprintf("💧 Влажность *%d%%*\n", w.Humidity))
 	sb.WriteString(fmt.Sprintf("🌀 Ветер *%s*\n", windDescription(w.WindSpeed, w.WindDirection)))
 	sb.WriteString(fmt.Sprintf("📉 Давление *%dмм*\n", HPaToMmHg(w.Pressure)))
+	if w.DayLength() > 0 {
+		sb.WriteString(fmt.Sprintf("🌅 Восход *%s*, закат *%s*, день *%s*\n", w.LocalTime(w.Sunrise).Format("15:04"), w.LocalTime(w.Sunset).Format("15:04"), FormatDuration(w.DayLength())))
+	}
 
 	return sb.String()
 }
@@ -52,3 +56,9 @@ func windDescription(windSpeed float64, windDirection string) string {
 	}
 	return fmt.Sprintf("%s %.1fм/c ", windDirection, windSpeed)
 }
+
+// FormatDuration formats a duration as hours and minutes, e.g. "10ч 27м"
+func FormatDuration(d time.Duration) string {
+	d = d.Round(time.Minute)
+	return fmt.Sprintf("%dч %dм", int(d.Hours()), int(d.Minutes())%60)
+}
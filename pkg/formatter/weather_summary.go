@@ -0,0 +1,20 @@
+package formatter
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/sushkevichd/day-guide-telegram-bot/pkg/domain"
+)
+
+type WeatherSummary struct{}
+
+func (_ *WeatherSummary) Format(s domain.WeatherSummary, days int) string {
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("📊 %s за %d дн.\n", s.Location, days))
+	sb.WriteString(fmt.Sprintf("🌡️ Температура от *%.1f°C* до *%.1f°C*, в среднем *%.1f°C*\n", s.TempMin, s.TempMax, s.TempAvg))
+	sb.WriteString(fmt.Sprintf("📉 Давление от *%dмм* до *%dмм*\n", HPaToMmHg(s.PressureMin), HPaToMmHg(s.PressureMax)))
+
+	return sb.String()
+}
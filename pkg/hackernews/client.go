@@ -0,0 +1,175 @@
+package hackernews
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sushkevichd/day-guide-telegram-bot/pkg/domain"
+	"github.com/sushkevichd/day-guide-telegram-bot/pkg/logger"
+)
+
+const (
+	baseURL = "https://hacker-news.firebaseio.com/v0"
+	itemURL = "https://news.ycombinator.com/item?id="
+
+	requestTimeout = 10 * time.Second
+	// Items are fetched one request each, the API has no batch endpoint
+	itemWorkers = 8
+)
+
+type client struct {
+	hc *http.Client
+}
+
+// NewClient creates the client of the official Hacker News API, it is public and needs no key
+func NewClient() *client {
+	return &client{
+		hc: &http.Client{Timeout: requestTimeout},
+	}
+}
+
+// FetchStories returns up to limit stories of the list ranked as on the site
+func (c *client) FetchStories(ctx context.Context, list domain.NewsList, limit int) ([]domain.NewsItem, error) {
+	var ids []int64
+	if err := c.get(ctx, fmt.Sprintf("%s/%sstories.json", baseURL, list), &ids); err != nil {
+		return nil, fmt.Errorf("fetching %s stories: %v", list, err)
+	}
+	if limit > 0 && limit < len(ids) {
+		ids = ids[:limit]
+	}
+
+	items := make([]*domain.NewsItem, len(ids))
+	idxCh := make(chan int)
+	var wg sync.WaitGroup
+	for range min(itemWorkers, len(ids)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range idxCh {
+				item, err := c.fetchItem(ctx, ids[i])
+				if err != nil {
+					slog.Warn("failed to fetch hacker news item", "id", ids[i], logger.Err(err))
+					continue
+				}
+				items[i] = item
+			}
+		}()
+	}
+
+	for i := range ids {
+		idxCh <- i
+	}
+	close(idxCh)
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	var stories []domain.NewsItem
+	for _, item := range items {
+		if item == nil {
+			continue
+		}
+		item.Rank = len(stories) + 1
+		stories = append(stories, *item)
+	}
+
+	if len(stories) == 0 && len(ids) > 0 {
+		return nil, fmt.Errorf("fetching %s stories: no item fetched", list)
+	}
+
+	return stories, nil
+}
+
+func (c *client) fetchItem(ctx context.Context, id int64) (*domain.NewsItem, error) {
+	var res itemAPIResponse
+	if err := c.get(ctx, fmt.Sprintf("%s/item/%d.json", baseURL, id), &res); err != nil {
+		return nil, err
+	}
+	if res.ID == 0 || res.Deleted || res.Dead {
+		return nil, fmt.Errorf("item is removed")
+	}
+
+	item := &domain.NewsItem{
+		ID:       strconv.FormatInt(res.ID, 10),
+		Title:    res.Title,
+		URL:      res.URL,
+		Score:    res.Score,
+		Author:   res.By,
+		Age:      age(time.Since(time.Unix(res.Time, 0))),
+		Comments: res.Descendants,
+	}
+
+	// Ask HN and other text posts link to their discussion
+	if item.URL == "" {
+		item.URL = itemURL + item.ID
+	}
+	if u, err := url.Parse(item.URL); err == nil {
+		item.Site = strings.TrimPrefix(u.Hostname(), "www.")
+	}
+
+	return item, nil
+}
+
+func (c *client) get(ctx context.Context, u string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return fmt.Errorf("creating request: %v", err)
+	}
+
+	resp, err := c.hc.Do(req)
+	if err != nil {
+		return fmt.Errorf("executing request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("error status: %d", resp.StatusCode)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("decoding response body: %v", err)
+	}
+
+	return nil
+}
+
+// age formats the duration the way the site does, e.g. "3 hours ago"
+func age(d time.Duration) string {
+	switch {
+	case d < time.Hour:
+		return plural(int(d.Minutes()), "minute")
+	case d < 24*time.Hour:
+		return plural(int(d.Hours()), "hour")
+	default:
+		return plural(int(d.Hours()/24), "day")
+	}
+}
+
+func plural(n int, unit string) string {
+	if n == 1 {
+		return fmt.Sprintf("1 %s ago", unit)
+	}
+	return fmt.Sprintf("%d %ss ago", n, unit)
+}
+
+type itemAPIResponse struct {
+	ID          int64  `json:"id"`
+	Deleted     bool   `json:"deleted"`
+	Dead        bool   `json:"dead"`
+	By          string `json:"by"`
+	Time        int64  `json:"time"`
+	Title       string `json:"title"`
+	URL         string `json:"url"`
+	Score       int    `json:"score"`
+	Descendants int    `json:"descendants"`
+}
@@ -6,7 +6,7 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
-	"reflect"
+	"time"
 
 	"github.com/sushkevichd/day-guide-telegram-bot/pkg/domain"
 )
@@ -25,7 +25,8 @@ func NewClient(appID string) *client {
 	}
 }
 
-func (c *client) FetchData(ctx context.Context, pair domain.CurrencyPair) (*domain.ExchangeRate, error) {
+// FetchData fetches the rates of all currencies against USD, the only base allowed by the free plan
+func (c *client) FetchData(ctx context.Context) (*domain.ExchangeRateSnapshot, error) {
 	u, err := url.Parse(baseURL)
 	if err != nil {
 		return nil, fmt.Errorf("parsing base url: %v", err)
@@ -33,8 +34,6 @@ func (c *client) FetchData(ctx context.Context, pair domain.CurrencyPair) (*doma
 
 	q := u.Query()
 	q.Set("app_id", c.appID)
-	q.Set("base", pair.Base.String())
-	q.Set("symbols", pair.Quote.String())
 
 	u.RawQuery = q.Encode()
 
@@ -47,8 +46,9 @@ func (c *client) FetchData(ctx context.Context, pair domain.CurrencyPair) (*doma
 	if err != nil {
 		return nil, fmt.Errorf("executing request: %v", err)
 	}
+	defer resp.Body.Close()
 
-	var res usdExchangeRateAPIResponse
+	var res latestAPIResponse
 	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
 		return nil, fmt.Errorf("decoding response body: %v", err)
 	}
@@ -57,206 +57,21 @@ func (c *client) FetchData(ctx context.Context, pair domain.CurrencyPair) (*doma
 		return nil, fmt.Errorf("error status: %d, message: %s", res.Status, res.Description)
 	}
 
-	rate, err := extractRateForCurrency(res, pair.Quote)
-	if err != nil {
-		return nil, fmt.Errorf("extracting rate for currency %s: %v", pair.Quote, err)
-	}
-
-	return &domain.ExchangeRate{
-		Pair: pair,
-		Rate: rate,
+	return &domain.ExchangeRateSnapshot{
+		Timestamp: time.Unix(res.Timestamp, 0).UTC(),
+		Base:      domain.Currency(res.Base),
+		Rates:     res.Rates,
 	}, nil
 }
 
-func extractRateForCurrency(resp usdExchangeRateAPIResponse, cur domain.Currency) (float64, error) {
-	ratesValue := reflect.ValueOf(resp.Rates)
-	rate := ratesValue.FieldByName(cur.String())
-
-	if !rate.IsValid() {
-		return 0, fmt.Errorf("invalid currency: %v", cur)
-	}
-
-	return rate.Float(), nil
-}
-
-type usdExchangeRateAPIResponse struct {
-	Disclaimer string `json:"disclaimer"`
-	License    string `json:"license"`
-	Timestamp  int    `json:"timestamp"`
-	Base       string `json:"base"`
-	Rates      struct {
-		AED float64 `json:"AED"`
-		AFN float64 `json:"AFN"`
-		ALL float64 `json:"ALL"`
-		AMD float64 `json:"AMD"`
-		ANG float64 `json:"ANG"`
-		AOA float64 `json:"AOA"`
-		ARS float64 `json:"ARS"`
-		AUD float64 `json:"AUD"`
-		AWG float64 `json:"AWG"`
-		AZN float64 `json:"AZN"`
-		BAM float64 `json:"BAM"`
-		BBD int     `json:"BBD"`
-		BDT float64 `json:"BDT"`
-		BGN float64 `json:"BGN"`
-		BHD float64 `json:"BHD"`
-		BIF float64 `json:"BIF"`
-		BMD int     `json:"BMD"`
-		BND float64 `json:"BND"`
-		BOB float64 `json:"BOB"`
-		BRL float64 `json:"BRL"`
-		BSD int     `json:"BSD"`
-		BTC float64 `json:"BTC"`
-		BTN float64 `json:"BTN"`
-		BWP float64 `json:"BWP"`
-		BYN float64 `json:"BYN"`
-		BZD float64 `json:"BZD"`
-		CAD float64 `json:"CAD"`
-		CDF float64 `json:"CDF"`
-		CHF float64 `json:"CHF"`
-		CLF float64 `json:"CLF"`
-		CLP float64 `json:"CLP"`
-		CNH float64 `json:"CNH"`
-		CNY float64 `json:"CNY"`
-		COP float64 `json:"COP"`
-		CRC float64 `json:"CRC"`
-		CUC int     `json:"CUC"`
-		CUP float64 `json:"CUP"`
-		CVE float64 `json:"CVE"`
-		CZK float64 `json:"CZK"`
-		DJF float64 `json:"DJF"`
-		DKK float64 `json:"DKK"`
-		DOP float64 `json:"DOP"`
-		DZD float64 `json:"DZD"`
-		EGP float64 `json:"EGP"`
-		ERN int     `json:"ERN"`
-		ETB float64 `json:"ETB"`
-		EUR float64 `json:"EUR"`
-		FJD float64 `json:"FJD"`
-		FKP float64 `json:"FKP"`
-		GBP float64 `json:"GBP"`
-		GEL float64 `json:"GEL"`
-		GGP float64 `json:"GGP"`
-		GHS float64 `json:"GHS"`
-		GIP float64 `json:"GIP"`
-		GMD float64 `json:"GMD"`
-		GNF float64 `json:"GNF"`
-		GTQ float64 `json:"GTQ"`
-		GYD float64 `json:"GYD"`
-		HKD float64 `json:"HKD"`
-		HNL float64 `json:"HNL"`
-		HRK float64 `json:"HRK"`
-		HTG float64 `json:"HTG"`
-		HUF float64 `json:"HUF"`
-		IDR float64 `json:"IDR"`
-		ILS float64 `json:"ILS"`
-		IMP float64 `json:"IMP"`
-		INR float64 `json:"INR"`
-		IQD float64 `json:"IQD"`
-		IRR int     `json:"IRR"`
-		ISK float64 `json:"ISK"`
-		JEP float64 `json:"JEP"`
-		JMD float64 `json:"JMD"`
-		JOD float64 `json:"JOD"`
-		JPY float64 `json:"JPY"`
-		KES float64 `json:"KES"`
-		KGS float64 `json:"KGS"`
-		KHR float64 `json:"KHR"`
-		KMF float64 `json:"KMF"`
-		KPW int     `json:"KPW"`
-		KRW float64 `json:"KRW"`
-		KWD float64 `json:"KWD"`
-		KYD float64 `json:"KYD"`
-		KZT float64 `json:"KZT"`
-		LAK float64 `json:"LAK"`
-		LBP float64 `json:"LBP"`
-		LKR float64 `json:"LKR"`
-		LRD float64 `json:"LRD"`
-		LSL float64 `json:"LSL"`
-		LYD float64 `json:"LYD"`
-		MAD float64 `json:"MAD"`
-		MDL float64 `json:"MDL"`
-		MGA float64 `json:"MGA"`
-		MKD float64 `json:"MKD"`
-		MMK float64 `json:"MMK"`
-		MNT int     `json:"MNT"`
-		MOP float64 `json:"MOP"`
-		MRU float64 `json:"MRU"`
-		MUR float64 `json:"MUR"`
-		MVR float64 `json:"MVR"`
-		MWK float64 `json:"MWK"`
-		MXN float64 `json:"MXN"`
-		MYR float64 `json:"MYR"`
-		MZN float64 `json:"MZN"`
-		NAD float64 `json:"NAD"`
-		NGN float64 `json:"NGN"`
-		NIO float64 `json:"NIO"`
-		NOK float64 `json:"NOK"`
-		NPR float64 `json:"NPR"`
-		NZD float64 `json:"NZD"`
-		OMR float64 `json:"OMR"`
-		PAB int     `json:"PAB"`
-		PEN float64 `json:"PEN"`
-		PGK float64 `json:"PGK"`
-		PHP float64 `json:"PHP"`
-		PKR float64 `json:"PKR"`
-		PLN float64 `json:"PLN"`
-		PYG float64 `json:"PYG"`
-		QAR float64 `json:"QAR"`
-		RON float64 `json:"RON"`
-		RSD float64 `json:"RSD"`
-		RUB float64 `json:"RUB"`
-		RWF float64 `json:"RWF"`
-		SAR float64 `json:"SAR"`
-		SBD float64 `json:"SBD"`
-		SCR float64 `json:"SCR"`
-		SDG float64 `json:"SDG"`
-		SEK float64 `json:"SEK"`
-		SGD float64 `json:"SGD"`
-		SHP float64 `json:"SHP"`
-		SLL float64 `json:"SLL"`
-		SOS float64 `json:"SOS"`
-		SRD float64 `json:"SRD"`
-		SSP float64 `json:"SSP"`
-		STD float64 `json:"STD"`
-		STN float64 `json:"STN"`
-		SVC float64 `json:"SVC"`
-		SYP float64 `json:"SYP"`
-		SZL float64 `json:"SZL"`
-		THB float64 `json:"THB"`
-		TJS float64 `json:"TJS"`
-		TMT float64 `json:"TMT"`
-		TND float64 `json:"TND"`
-		TOP float64 `json:"TOP"`
-		TRY float64 `json:"TRY"`
-		TTD float64 `json:"TTD"`
-		TWD float64 `json:"TWD"`
-		TZS int     `json:"TZS"`
-		UAH float64 `json:"UAH"`
-		UGX float64 `json:"UGX"`
-		USD int     `json:"USD"`
-		UYU float64 `json:"UYU"`
-		UZS float64 `json:"UZS"`
-		VES float64 `json:"VES"`
-		VND float64 `json:"VND"`
-		VUV float64 `json:"VUV"`
-		WST float64 `json:"WST"`
-		XAF float64 `json:"XAF"`
-		XAG float64 `json:"XAG"`
-		XAU float64 `json:"XAU"`
-		XCD float64 `json:"XCD"`
-		XDR float64 `json:"XDR"`
-		XOF float64 `json:"XOF"`
-		XPD float64 `json:"XPD"`
-		XPF float64 `json:"XPF"`
-		XPT float64 `json:"XPT"`
-		YER float64 `json:"YER"`
-		ZAR float64 `json:"ZAR"`
-		ZMW float64 `json:"ZMW"`
-		ZWL int     `json:"ZWL"`
-	} `json:"rates"`
-	Error       bool   `json:"error"`
-	Status      int    `json:"status"`
-	Message     string `json:"message"`
-	Description string `json:"description"`
+type latestAPIResponse struct {
+	Disclaimer  string             `json:"disclaimer"`
+	License     string             `json:"license"`
+	Timestamp   int64              `json:"timestamp"`
+	Base        string             `json:"base"`
+	Rates       map[string]float64 `json:"rates"`
+	Error       bool               `json:"error"`
+	Status      int                `json:"status"`
+	Message     string             `json:"message"`
+	Description string             `json:"description"`
 }
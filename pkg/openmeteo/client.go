@@ -0,0 +1,145 @@
+package openmeteo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/sushkevichd/day-guide-telegram-bot/pkg/domain"
+)
+
+const baseURL = "https://api.open-meteo.com/v1/forecast"
+
+type client struct {
+	hc *http.Client
+}
+
+// NewClient creates the Open-Meteo client, the API is free for non-commercial use and needs no key
+func NewClient() *client {
+	return &client{
+		hc: &http.Client{},
+	}
+}
+
+func (c *client) FetchData(ctx context.Context, location domain.Location) (*domain.Weather, error) {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("parsing base url: %v", err)
+	}
+
+	q := u.Query()
+	q.Set("latitude", strconv.FormatFloat(location.Lat, 'f', -1, 64))
+	q.Set("longitude", strconv.FormatFloat(location.Lon, 'f', -1, 64))
+	q.Set("current", "temperature_2m,apparent_temperature,relative_humidity_2m,pressure_msl,weather_code,wind_speed_10m,wind_direction_10m")
+	q.Set("daily", "sunrise,sunset")
+	q.Set("forecast_days", "1")
+	q.Set("timezone", "auto")
+	q.Set("timeformat", "unixtime")
+	q.Set("wind_speed_unit", "ms")
+
+	u.RawQuery = q.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %v", err)
+	}
+
+	resp, err := c.hc.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("executing request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	var res forecastAPIResponse
+	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		return nil, fmt.Errorf("decoding response body: %v", err)
+	}
+
+	if res.Error {
+		return nil, fmt.Errorf("error status: %d, message: %s", resp.StatusCode, res.Reason)
+	}
+
+	weather, weatherVerbose := describeWeatherCode(res.Current.WeatherCode)
+
+	w := &domain.Weather{
+		Location:       location.Name,
+		Lat:            location.Lat,
+		Lon:            location.Lon,
+		Temp:           res.Current.Temperature,
+		TempFeel:       res.Current.ApparentTemperature,
+		Pressure:       int(math.Round(res.Current.SeaLevelPressure)),
+		Humidity:       res.Current.RelativeHumidity,
+		Weather:        weather,
+		WeatherVerbose: weatherVerbose,
+		WindSpeed:      res.Current.WindSpeed,
+		WindDirection:  domain.WindDirection(int(math.Round(res.Current.WindDirection))),
+		TimezoneOffset: res.UTCOffsetSeconds,
+	}
+	if len(res.Daily.Sunrise) > 0 && len(res.Daily.Sunset) > 0 {
+		w.Sunrise = time.Unix(res.Daily.Sunrise[0], 0).UTC()
+		w.Sunset = time.Unix(res.Daily.Sunset[0], 0).UTC()
+	}
+
+	return w, nil
+}
+
+// describeWeatherCode maps a WMO weather code to the OpenWeatherMap main group used for icons and a description
+func describeWeatherCode(code int) (string, string) {
+	switch code {
+	case 0:
+		return "Clear", "ясно"
+	case 1:
+		return "Clouds", "преимущественно ясно"
+	case 2:
+		return "Clouds", "переменная облачность"
+	case 3:
+		return "Clouds", "пасмурно"
+	case 45, 48:
+		return "Fog", "туман"
+	case 51, 53, 55, 56, 57:
+		return "Drizzle", "морось"
+	case 61, 66:
+		return "Rain", "небольшой дождь"
+	case 63:
+		return "Rain", "дождь"
+	case 65, 67:
+		return "Rain", "сильный дождь"
+	case 80, 81, 82:
+		return "Rain", "ливень"
+	case 71, 77, 85:
+		return "Snow", "небольшой снег"
+	case 73:
+		return "Snow", "снег"
+	case 75, 86:
+		return "Snow", "сильный снег"
+	case 95, 96, 99:
+		return "Thunderstorm", "гроза"
+	default:
+		return "", "-"
+	}
+}
+
+type forecastAPIResponse struct {
+	UTCOffsetSeconds int `json:"utc_offset_seconds"`
+	Current          struct {
+		Time                int64   `json:"time"`
+		Temperature         float64 `json:"temperature_2m"`
+		ApparentTemperature float64 `json:"apparent_temperature"`
+		RelativeHumidity    int     `json:"relative_humidity_2m"`
+		SeaLevelPressure    float64 `json:"pressure_msl"`
+		WeatherCode         int     `json:"weather_code"`
+		WindSpeed           float64 `json:"wind_speed_10m"`
+		WindDirection       float64 `json:"wind_direction_10m"`
+	} `json:"current"`
+	Daily struct {
+		Sunrise []int64 `json:"sunrise"`
+		Sunset  []int64 `json:"sunset"`
+	} `json:"daily"`
+	Error  bool   `json:"error"`
+	Reason string `json:"reason"`
+}
@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"strconv"
+	"time"
 
 	"github.com/sushkevichd/day-guide-telegram-bot/pkg/domain"
 )
@@ -31,7 +33,8 @@ func (c *client) FetchData(ctx context.Context, location domain.Location) (*doma
 	}
 
 	q := u.Query()
-	q.Set("q", string(location))
+	q.Set("lat", strconv.FormatFloat(location.Lat, 'f', -1, 64))
+	q.Set("lon", strconv.FormatFloat(location.Lon, 'f', -1, 64))
 	q.Set("appid", c.apiKey)
 	q.Set("units", "metric")
 	q.Set("lang", "ru")
@@ -58,7 +61,9 @@ func (c *client) FetchData(ctx context.Context, location domain.Location) (*doma
 	}
 
 	return &domain.Weather{
-		Location:       res.Name,
+		Location:       location.Name,
+		Lat:            location.Lat,
+		Lon:            location.Lon,
 		Temp:           res.Main.Temp,
 		TempFeel:       res.Main.FeelsLike,
 		Pressure:       res.Main.Pressure,
@@ -66,33 +71,19 @@ func (c *client) FetchData(ctx context.Context, location domain.Location) (*doma
 		Weather:        res.Weather[0].Main,
 		WeatherVerbose: res.Weather[0].Description,
 		WindSpeed:      res.Wind.Speed,
-		WindDirection:  convertWindDirection(res.Wind.Deg),
+		WindDirection:  domain.WindDirection(res.Wind.Deg),
+		Sunrise:        unixTime(res.Sys.Sunrise),
+		Sunset:         unixTime(res.Sys.Sunset),
+		TimezoneOffset: res.Timezone,
 	}, nil
 }
 
-func convertWindDirection(d int) string {
-	if d == 0 {
-		return "-"
-	}
-
-	switch {
-	case 24 <= d && d <= 68:
-		return "северо-восточный"
-	case 69 <= d && d <= 113:
-		return "восточный"
-	case 114 <= d && d <= 158:
-		return "юго-восточный"
-	case 159 <= d && d <= 203:
-		return "южный"
-	case 204 <= d && d <= 248:
-		return "юго-западный"
-	case 249 <= d && d <= 293:
-		return "западный"
-	case 294 <= d && d <= 338:
-		return "северо-западный"
-	default:
-		return "северный"
+// unixTime keeps the time zero when the sun does not rise or set, e.g. during polar day
+func unixTime(sec int) time.Time {
+	if sec == 0 {
+		return time.Time{}
 	}
+	return time.Unix(int64(sec), 0).UTC()
 }
 
 type weatherAPIResponse struct {
@@ -0,0 +1,139 @@
+package openweathermap
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/sushkevichd/day-guide-telegram-bot/pkg/domain"
+)
+
+const forecastURL = "https://api.openweathermap.org/data/2.5/forecast"
+
+type forecastClient struct {
+	apiKey string
+	hc     *http.Client
+}
+
+func NewForecastClient(apiKey string) *forecastClient {
+	return &forecastClient{
+		apiKey: apiKey,
+		hc:     &http.Client{},
+	}
+}
+
+// FetchData fetches the 5 day / 3 hour forecast and summarizes it per local day of the location
+func (c *forecastClient) FetchData(ctx context.Context, location domain.Location) (*domain.Forecast, error) {
+	u, err := url.Parse(forecastURL)
+	if err != nil {
+		return nil, fmt.Errorf("parsing forecast url: %v", err)
+	}
+
+	q := u.Query()
+	q.Set("lat", strconv.FormatFloat(location.Lat, 'f', -1, 64))
+	q.Set("lon", strconv.FormatFloat(location.Lon, 'f', -1, 64))
+	q.Set("appid", c.apiKey)
+	q.Set("units", "metric")
+	q.Set("lang", "ru")
+
+	u.RawQuery = q.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %v", err)
+	}
+
+	resp, err := c.hc.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("executing request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	var res forecastAPIResponse
+	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		return nil, fmt.Errorf("decoding response body: %v", err)
+	}
+
+	// cod is a string in successful forecast responses and a number in some errors, the HTTP status is reliable
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("error status: %d, message: %v", resp.StatusCode, res.Message)
+	}
+
+	return &domain.Forecast{
+		Location:       location.Name,
+		Lat:            location.Lat,
+		Lon:            location.Lon,
+		TimezoneOffset: res.City.Timezone,
+		Days:           summarizeDays(res.List, res.City.Timezone),
+	}, nil
+}
+
+func summarizeDays(entries []forecastEntry, timezoneOffset int) []domain.ForecastDay {
+	var days []domain.ForecastDay
+	weatherCounts := map[string]int{}
+
+	for _, e := range entries {
+		local := time.Unix(e.Dt, 0).UTC().Add(time.Duration(timezoneOffset) * time.Second)
+		date := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, time.UTC)
+
+		if len(days) == 0 || !days[len(days)-1].Date.Equal(date) {
+			days = append(days, domain.ForecastDay{
+				Date:    date,
+				TempMin: math.Inf(1),
+				TempMax: math.Inf(-1),
+			})
+			clear(weatherCounts)
+		}
+		day := &days[len(days)-1]
+
+		day.TempMin = math.Min(day.TempMin, e.Main.TempMin)
+		day.TempMax = math.Max(day.TempMax, e.Main.TempMax)
+		day.PrecipitationProbability = max(day.PrecipitationProbability, int(math.Round(e.Pop*100)))
+		day.WindSpeed = math.Max(day.WindSpeed, e.Wind.Speed)
+
+		// The day is described by its most frequent weather
+		if len(e.Weather) > 0 {
+			w := e.Weather[0]
+			weatherCounts[w.Main]++
+			if day.Weather == "" || weatherCounts[w.Main] > weatherCounts[day.Weather] {
+				day.Weather = w.Main
+				day.WeatherVerbose = w.Description
+			}
+		}
+	}
+
+	return days
+}
+
+type forecastAPIResponse struct {
+	Message any             `json:"message"`
+	List    []forecastEntry `json:"list"`
+	City    struct {
+		Name     string `json:"name"`
+		Timezone int    `json:"timezone"`
+		Sunrise  int64  `json:"sunrise"`
+		Sunset   int64  `json:"sunset"`
+	} `json:"city"`
+}
+
+type forecastEntry struct {
+	Dt   int64 `json:"dt"`
+	Main struct {
+		Temp    float64 `json:"temp"`
+		TempMin float64 `json:"temp_min"`
+		TempMax float64 `json:"temp_max"`
+	} `json:"main"`
+	Weather []struct {
+		Main        string `json:"main"`
+		Description string `json:"description"`
+	} `json:"weather"`
+	Wind struct {
+		Speed float64 `json:"speed"`
+	} `json:"wind"`
+	Pop float64 `json:"pop"` // probability of precipitation, 0..1
+}
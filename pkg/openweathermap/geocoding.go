@@ -0,0 +1,87 @@
+package openweathermap
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/sushkevichd/day-guide-telegram-bot/pkg/domain"
+)
+
+const geocodingURL = "https://api.openweathermap.org/geo/1.0/direct"
+
+var ErrLocationNotFound = errors.New("location not found")
+
+type geocodingClient struct {
+	apiKey string
+	hc     *http.Client
+}
+
+func NewGeocodingClient(apiKey string) *geocodingClient {
+	return &geocodingClient{
+		apiKey: apiKey,
+		hc:     &http.Client{},
+	}
+}
+
+// Geocode resolves a city name to coordinates, naming the location in Russian when possible
+func (c *geocodingClient) Geocode(ctx context.Context, query string) (*domain.Location, error) {
+	u, err := url.Parse(geocodingURL)
+	if err != nil {
+		return nil, fmt.Errorf("parsing base url: %v", err)
+	}
+
+	q := u.Query()
+	q.Set("q", query)
+	q.Set("limit", "1")
+	q.Set("appid", c.apiKey)
+
+	u.RawQuery = q.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %v", err)
+	}
+
+	resp, err := c.hc.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("executing request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	var res []geocodingAPIResponse
+	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		return nil, fmt.Errorf("decoding response body: %v", err)
+	}
+
+	if len(res) == 0 {
+		return nil, ErrLocationNotFound
+	}
+
+	name := res[0].Name
+	if ru, ok := res[0].LocalNames["ru"]; ok && ru != "" {
+		name = ru
+	}
+
+	return &domain.Location{
+		Name: name,
+		Lat:  res[0].Lat,
+		Lon:  res[0].Lon,
+	}, nil
+}
+
+type geocodingAPIResponse struct {
+	Name       string            `json:"name"`
+	LocalNames map[string]string `json:"local_names"`
+	Lat        float64           `json:"lat"`
+	Lon        float64           `json:"lon"`
+	Country    string            `json:"country"`
+	State      string            `json:"state"`
+}
@@ -0,0 +1,61 @@
+package parser
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/sushkevichd/day-guide-telegram-bot/pkg/domain"
+)
+
+// HolidayParser parses a day page of the "What Holiday Is It Today" site saved by the holyscrape tool
+type HolidayParser struct{}
+
+func (p HolidayParser) Parse(r io.Reader) ([]domain.Holiday, error) {
+	doc, err := goquery.NewDocumentFromReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create document: %w", err)
+	}
+
+	var holidays []domain.Holiday
+
+	// Each holiday of the day is an answer of the listing, in the order of popularity.
+	doc.Find("div.listing_wr [itemprop=suggestedAnswer], div.listing_wr [itemprop=acceptedAnswer]").Each(func(i int, s *goquery.Selection) {
+		name := strings.Join(strings.Fields(s.Find("span[itemprop=text]").First().Text()), " ")
+		if name == "" {
+			return
+		}
+
+		name, categories := splitCategories(name)
+		holidays = append(holidays, domain.Holiday{
+			OrderNumber: len(holidays) + 1,
+			Name:        name,
+			Categories:  categories,
+		})
+	})
+
+	if len(holidays) == 0 {
+		return nil, fmt.Errorf("no holidays found")
+	}
+
+	return holidays, nil
+}
+
+// splitCategories cuts the categories off the name, they are listed in brackets at the end, e.g.
+// "Новый год [Международные праздники, Праздники России]"
+func splitCategories(name string) (string, []domain.HolidayCategory) {
+	open := strings.LastIndex(name, "[")
+	if open == -1 || !strings.HasSuffix(name, "]") {
+		return name, nil
+	}
+
+	var categories []domain.HolidayCategory
+	for _, c := range strings.Split(name[open+1:len(name)-1], ",") {
+		if c = strings.TrimSpace(c); c != "" {
+			categories = append(categories, domain.HolidayCategory{Name: c})
+		}
+	}
+
+	return strings.TrimSpace(name[:open]), categories
+}
@@ -38,14 +38,18 @@ func NewExchangeRatePlot(
 	}
 }
 
-func (e *exchangeRatePlot) Generate(ctx context.Context, pair domain.CurrencyPair) ([]byte, string, error) {
+// Generate renders the chart of the daily rates for the given number of days and the caption with the latest rate
+func (e *exchangeRatePlot) Generate(ctx context.Context, pair domain.CurrencyPair, days int) ([]byte, string, error) {
 	// Create image
 	graph := chart.Chart{}
 
-	rates, err := e.fetcher.FetchHistoryRate(ctx, pair, 30)
+	rates, err := e.fetcher.FetchHistoryRate(ctx, pair, days)
 	if err != nil {
 		return nil, "", fmt.Errorf("fetching latest exchange rate for pair %s: %v", pair, err)
 	}
+	if len(rates) == 0 {
+		return nil, "", fmt.Errorf("%w in pair %s", domain.ErrUnknownCurrency, pair)
+	}
 
 	var xValues []time.Time
 	var yValues []float64
@@ -0,0 +1,71 @@
+package report
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/sushkevichd/day-guide-telegram-bot/pkg/domain"
+	"github.com/sushkevichd/day-guide-telegram-bot/pkg/logger"
+)
+
+type ForecastFetcher interface {
+	FetchByLocation(ctx context.Context, location domain.Location, days int) (*domain.Forecast, error)
+}
+
+type ForecastFormatter interface {
+	Format(forecast domain.Forecast) string
+}
+
+type forecast struct {
+	locationFetcher LocationFetcher
+	fetcher         ForecastFetcher
+	formatter       ForecastFormatter
+}
+
+func NewForecast(
+	locationFetcher LocationFetcher,
+	fetcher ForecastFetcher,
+	formatter ForecastFormatter,
+) *forecast {
+	return &forecast{
+		locationFetcher: locationFetcher,
+		fetcher:         fetcher,
+		formatter:       formatter,
+	}
+}
+
+// Generate generates the forecast for the given number of days for the chat location named city, or for all
+// locations of the chat when city is empty
+func (f *forecast) Generate(ctx context.Context, chatID int64, city string, days int) (string, error) {
+	locations, err := f.locationFetcher.FetchByChatID(ctx, chatID)
+	if err != nil {
+		return "", fmt.Errorf("fetching locations for chat %d: %v", chatID, err)
+	}
+
+	if len(locations) == 0 {
+		return "Не выбрано ни одного города. Добавьте город командой /weather add <город>", nil
+	}
+
+	locations = locationsByName(locations, city)
+	if len(locations) == 0 {
+		return fmt.Sprintf("Город %s не добавлен. Добавьте его командой /weather add %s", city, city), nil
+	}
+
+	var sb strings.Builder
+	for _, loc := range locations {
+		forecast, err := f.fetcher.FetchByLocation(ctx, loc, days)
+		if err != nil || len(forecast.Days) == 0 {
+			// A freshly added location has no data until the next loader pass
+			slog.Warn("fetching forecast", "location", loc.Name, logger.Err(err))
+			sb.WriteString(fmt.Sprintf("❓ %s - нет прогноза\n\n", loc.Name))
+			continue
+		}
+
+		sb.WriteString(f.formatter.Format(*forecast))
+		sb.WriteString("\n")
+	}
+
+	return sb.String(), nil
+}
@@ -3,6 +3,7 @@ package report
 import (
 	"context"
 	"fmt"
+	"log/slog"
 	"strings"
 	"time"
 
@@ -10,10 +11,24 @@ import (
 	"golang.org/x/text/message"
 
 	"github.com/sushkevichd/day-guide-telegram-bot/pkg/domain"
+	"github.com/sushkevichd/day-guide-telegram-bot/pkg/formatter"
+	"github.com/sushkevichd/day-guide-telegram-bot/pkg/logger"
 )
 
+// Maximal number of holidays found by a search
+const holidaySearchLimit = 20
+
 type HolidaysFetcher interface {
 	FetchByDate(ctx context.Context, date time.Time) ([]domain.Holiday, error)
+	SearchByName(ctx context.Context, text string, since time.Time) ([]domain.Holiday, error)
+}
+
+type HolidayCategoryPreferences interface {
+	FetchHiddenCategoryIDs(ctx context.Context, chatID int64) ([]int64, error)
+}
+
+type ChatEventsFetcher interface {
+	FetchByChatID(ctx context.Context, chatID int64) ([]domain.ChatEvent, error)
 }
 
 type HolidaysFormatter interface {
@@ -21,41 +36,169 @@ type HolidaysFormatter interface {
 }
 
 type holiday struct {
-	fetcher HolidaysFetcher
+	fetcher       HolidaysFetcher
+	eventsFetcher ChatEventsFetcher
+	preferences   HolidayCategoryPreferences
 }
 
 func NewHoliday(
 	fetcher HolidaysFetcher,
+	eventsFetcher ChatEventsFetcher,
+	preferences HolidayCategoryPreferences,
 ) *holiday {
 	return &holiday{
-		fetcher: fetcher,
+		fetcher:       fetcher,
+		eventsFetcher: eventsFetcher,
+		preferences:   preferences,
 	}
 }
 
-func (h *holiday) Generate(ctx context.Context) (string, error) {
-	now := time.Now()
+// Generate lists the holidays of the day of now followed by the events of the chat, the day's and the upcoming ones to
+// remind about. now is expected in the chat's timezone.
+func (h *holiday) Generate(ctx context.Context, chatID int64, now time.Time) (string, error) {
+	hidden, err := h.hiddenCategories(ctx, chatID)
+	if err != nil {
+		return "", err
+	}
 
-	holidays, err := h.fetcher.FetchByDate(ctx, now)
+	holidays, err := h.fetchByDate(ctx, now, hidden)
 	if err != nil {
-		return "", fmt.Errorf("fetching holidays for date %s: %v", now, err)
+		return "", err
 	}
 
+	var resp string
 	if len(holidays) == 0 {
-		return "Сегодня нет официальных праздников. Наслаждайтесь обычным днём!", nil
+		resp = "Сегодня нет официальных праздников. Наслаждайтесь обычным днём!"
+	} else {
+		resp = fmt.Sprintf("🎉 *%s: Какие праздники отмечаем?* 🎉\n\n", formatDate(now)) + joinHolidays(holidays)
 	}
 
-	holidaysStr := joinHolidays(holidays)
+	// The chat events are an addition, the holidays are still reported without them
+	events, err := h.eventsFetcher.FetchByChatID(ctx, chatID)
+	if err != nil {
+		slog.Error("failed to fetch chat events", "chatID", chatID, logger.Err(err))
+		return resp, nil
+	}
+	if eventsStr := joinChatEvents(events, now); eventsStr != "" {
+		resp += "\n\n📌 *События чата*\n" + eventsStr
+	}
 
-	resp := fmt.Sprintf("🎉 *%s: Какие праздники отмечаем?* 🎉\n\n", formatDate(now)) + holidaysStr
 	return resp, nil
 }
 
+func (h *holiday) GenerateForDate(ctx context.Context, chatID int64, date time.Time) (string, error) {
+	hidden, err := h.hiddenCategories(ctx, chatID)
+	if err != nil {
+		return "", err
+	}
+
+	holidays, err := h.fetchByDate(ctx, date, hidden)
+	if err != nil {
+		return "", err
+	}
+
+	if len(holidays) == 0 {
+		return fmt.Sprintf("На %s официальных праздников нет.", formatDate(date)), nil
+	}
+
+	return fmt.Sprintf("🎉 *%s: Какие праздники отмечаем?* 🎉\n\n", formatDate(date)) + joinHolidays(holidays), nil
+}
+
+// GenerateWeek lists the holidays of 7 days starting from the date
+func (h *holiday) GenerateWeek(ctx context.Context, chatID int64, from time.Time) (string, error) {
+	hidden, err := h.hiddenCategories(ctx, chatID)
+	if err != nil {
+		return "", err
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("🗓️ *Праздники %s - %s*\n", formatDate(from), formatDate(from.AddDate(0, 0, 6))))
+
+	for i := range 7 {
+		date := from.AddDate(0, 0, i)
+		holidays, err := h.fetchByDate(ctx, date, hidden)
+		if err != nil {
+			return "", err
+		}
+		if len(holidays) == 0 {
+			continue
+		}
+
+		sb.WriteString(fmt.Sprintf("\n*%s, %s*\n", russianWeekdays()[date.Weekday()], formatDate(date)))
+		sb.WriteString(joinHolidays(holidays))
+		sb.WriteString("\n")
+	}
+
+	return sb.String(), nil
+}
+
+// Search lists the holidays which name contains the text by the date of their next celebration after the chat's now
+func (h *holiday) Search(ctx context.Context, chatID int64, text string, now time.Time) (string, error) {
+	hidden, err := h.hiddenCategories(ctx, chatID)
+	if err != nil {
+		return "", err
+	}
+
+	found, err := h.fetcher.SearchByName(ctx, text, now)
+	if err != nil {
+		return "", fmt.Errorf("searching holidays by %q: %v", text, err)
+	}
+	holidays := visibleHolidays(found, hidden)
+	holidays = holidays[:min(len(holidays), holidaySearchLimit)]
+
+	// The query is echoed in the reply
+	text = formatter.StripMarkdown(text)
+
+	if len(holidays) == 0 {
+		return fmt.Sprintf("Праздники по запросу «%s» не найдены.", text), nil
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("🔎 *Праздники по запросу «%s»*\n\n", text))
+	for _, holiday := range holidays {
+		sb.WriteString(fmt.Sprintf("*%s* - %s\n", formatDate(holiday.Date), joinHolidays([]domain.Holiday{holiday})))
+	}
+
+	return sb.String(), nil
+}
+
+func (h *holiday) hiddenCategories(ctx context.Context, chatID int64) (map[int64]bool, error) {
+	ids, err := h.preferences.FetchHiddenCategoryIDs(ctx, chatID)
+	if err != nil {
+		return nil, fmt.Errorf("fetching hidden holiday categories: %v", err)
+	}
+
+	hidden := make(map[int64]bool, len(ids))
+	for _, id := range ids {
+		hidden[id] = true
+	}
+	return hidden, nil
+}
+
+func (h *holiday) fetchByDate(ctx context.Context, date time.Time, hidden map[int64]bool) ([]domain.Holiday, error) {
+	holidays, err := h.fetcher.FetchByDate(ctx, date)
+	if err != nil {
+		return nil, fmt.Errorf("fetching holidays for date %s: %v", date.Format(time.DateOnly), err)
+	}
+	return visibleHolidays(holidays, hidden), nil
+}
+
+func visibleHolidays(holidays []domain.Holiday, hidden map[int64]bool) []domain.Holiday {
+	var visible []domain.Holiday
+	for _, holiday := range holidays {
+		if holiday.Visible(hidden) {
+			visible = append(visible, holiday)
+		}
+	}
+	return visible
+}
+
 func joinHolidays(holidays []domain.Holiday) string {
 	names := make([]string, 0, len(holidays))
 	for _, holiday := range holidays {
 		var icons string
 		for _, category := range holiday.Categories {
-			icons += getEmoji(category)
+			icons += category.Emoji
 		}
 		names = append(names, fmt.Sprintf("%s %s", icons, holiday.Name))
 	}
@@ -63,20 +206,38 @@ func joinHolidays(holidays []domain.Holiday) string {
 	return strings.Join(names, "\n")
 }
 
-func getEmoji(category string) string {
-	switch category {
-	case "Международные праздники":
-		return "🌍"
-	case "Праздники России":
-		return "🇷🇺"
-	case "Праздники славян":
-		return "🪆"
-	case "Праздники ООН":
-		return "🤝"
-	case "Православные праздники":
-		return "✝️"
+func joinChatEvents(events []domain.ChatEvent, today time.Time) string {
+	var lines []string
+	for _, e := range events {
+		days, ok := e.DaysLeft(today)
+		switch {
+		case !ok || days > e.RemindDays:
+		case days == 0:
+			line := "🎈 " + e.Name
+			if years := e.Anniversary(today); years > 0 {
+				line += fmt.Sprintf(" - %d %s", years, pluralize(years, "год", "года", "лет"))
+			}
+			lines = append(lines, line)
+		default:
+			date := today.AddDate(0, 0, days)
+			lines = append(lines, fmt.Sprintf("⏳ %s - через %d %s, %s", e.Name, days, pluralize(days, "день", "дня", "дней"), formatDate(date)))
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// pluralize picks the Russian form of the noun for the number, e.g. 1 день, 2 дня, 5 дней
+func pluralize(n int, one, few, many string) string {
+	switch {
+	case n%100 >= 11 && n%100 <= 14:
+		return many
+	case n%10 == 1:
+		return one
+	case n%10 >= 2 && n%10 <= 4:
+		return few
 	default:
-		return "❓"
+		return many
 	}
 }
 
@@ -92,3 +253,9 @@ func russianMonths() []string {
 		"августа", "сентября", "октября", "ноября", "декабря",
 	}
 }
+
+func russianWeekdays() []string {
+	return []string{
+		"воскресенье", "понедельник", "вторник", "среда", "четверг", "пятница", "суббота",
+	}
+}
@@ -0,0 +1,70 @@
+package report
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/sushkevichd/day-guide-telegram-bot/pkg/domain"
+)
+
+type MoonCalculator interface {
+	PhaseAt(t time.Time) domain.MoonPhase
+	NextPhases(from time.Time) []domain.MoonPhaseEvent
+}
+
+type MoonCalendarFormatter interface {
+	Format(domain.MoonPhase) string
+	FormatEvents(events []domain.MoonPhaseEvent) string
+}
+
+// ChatLocationFetcher returns the chat's timezone, the default one for chats not registered yet
+type ChatLocationFetcher interface {
+	FetchLocation(ctx context.Context, chatID int64) (*time.Location, error)
+}
+
+type moonCalendar struct {
+	locations  ChatLocationFetcher
+	calculator MoonCalculator
+	formatter  MoonCalendarFormatter
+}
+
+func NewMoonCalendar(
+	locations ChatLocationFetcher,
+	calculator MoonCalculator,
+	formatter MoonCalendarFormatter,
+) *moonCalendar {
+	return &moonCalendar{
+		locations:  locations,
+		calculator: calculator,
+		formatter:  formatter,
+	}
+}
+
+// Generate lists the upcoming principal moon phases in the chat's timezone
+func (m *moonCalendar) Generate(ctx context.Context, chatID int64) (string, error) {
+	loc, err := m.locations.FetchLocation(ctx, chatID)
+	if err != nil {
+		return "", err
+	}
+
+	events := m.calculator.NextPhases(time.Now())
+	for i := range events {
+		events[i].Time = events[i].Time.In(loc)
+	}
+
+	return m.formatter.FormatEvents(events), nil
+}
+
+// GenerateForDate reports the moon phase at noon of the date in the chat's timezone
+func (m *moonCalendar) GenerateForDate(ctx context.Context, chatID int64, date time.Time) (string, error) {
+	loc, err := m.locations.FetchLocation(ctx, chatID)
+	if err != nil {
+		return "", err
+	}
+
+	noon := time.Date(date.Year(), date.Month(), date.Day(), 12, 0, 0, 0, loc)
+	phase := m.calculator.PhaseAt(noon)
+
+	return fmt.Sprintf("📅 %s\n%s", noon.Format("02.01.2006"), m.formatter.Format(phase)), nil
+}
@@ -2,9 +2,15 @@ package report
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log/slog"
+	"strings"
+	"time"
 
 	"github.com/sushkevichd/day-guide-telegram-bot/pkg/domain"
+	"github.com/sushkevichd/day-guide-telegram-bot/pkg/logger"
+	"github.com/sushkevichd/day-guide-telegram-bot/pkg/repository"
 )
 
 const moonPhaseMessageSetupPrompt = `
@@ -17,28 +23,97 @@ type MoonPhaseFetcher interface {
 
 type MoonPhaseFormatter interface {
 	Format(domain.MoonPhase) string
+	FormatAdvice(advice string) string
+}
+
+type MoonAdviceGenerator interface {
+	GenerateResponse(ctx context.Context, messages []domain.GMessage) (domain.GMessage, error)
+}
+
+type MoonAdviceStore interface {
+	SaveAdvice(ctx context.Context, date time.Time, age int, advice string) error
+	FetchAdvice(ctx context.Context, date time.Time, age int) (string, error)
 }
 
 type moonPhase struct {
 	fetcher   MoonPhaseFetcher
 	formatter MoonPhaseFormatter
+	generator MoonAdviceGenerator
+	store     MoonAdviceStore
 }
 
 func NewMoonPhase(
 	fetcher MoonPhaseFetcher,
 	formatter MoonPhaseFormatter,
+	generator MoonAdviceGenerator,
+	store MoonAdviceStore,
 ) *moonPhase {
 	return &moonPhase{
 		fetcher:   fetcher,
 		formatter: formatter,
+		generator: generator,
+		store:     store,
 	}
 }
 
-func (m *moonPhase) Generate(ctx context.Context) (string, error) {
+// Generate reports the latest moon phase with the advice for the lunar day, now is expected in the chat's timezone
+func (m *moonPhase) Generate(ctx context.Context, _ int64, now time.Time) (string, error) {
 	phase, err := m.fetcher.FetchLatestPhase(ctx)
 	if err != nil {
 		return "", fmt.Errorf("fetching latest moon phase: %v", err)
 	}
 
-	return m.formatter.Format(*phase), nil
+	text := m.formatter.Format(*phase)
+
+	// The advice is an addition, the phase is still reported without it
+	advice, err := m.advice(ctx, *phase, now)
+	if err != nil {
+		slog.Error("failed to get moon advice", "age", phase.Age, logger.Err(err))
+		return text, nil
+	}
+
+	return text + m.formatter.FormatAdvice(advice), nil
+}
+
+// advice returns the advice for the lunar day, generating it only once a day so repeated reports don't call the model
+func (m *moonPhase) advice(ctx context.Context, phase domain.MoonPhase, today time.Time) (string, error) {
+	advice, err := m.store.FetchAdvice(ctx, today, phase.Age)
+	if err == nil {
+		return advice, nil
+	}
+	if !errors.Is(err, repository.ErrMoonAdviceNotFound) {
+		return "", fmt.Errorf("fetching cached advice: %v", err)
+	}
+
+	resp, err := m.generator.GenerateResponse(ctx, []domain.GMessage{
+		{
+			Role: "user",
+			Parts: []domain.GMessagePart{
+				{
+					Text: fmt.Sprintf("Сегодня %d-й лунный день, фаза Луны - %s.", phase.Age, phase.Phase),
+				},
+				{
+					Text: moonPhaseMessageSetupPrompt,
+				},
+			},
+		},
+	})
+	if err != nil {
+		return "", fmt.Errorf("generating advice: %v", err)
+	}
+
+	var sb strings.Builder
+	for _, p := range resp.Parts {
+		sb.WriteString(p.Text)
+	}
+	advice = strings.TrimSpace(sb.String())
+	if advice == "" {
+		return "", errors.New("generating advice: empty response")
+	}
+
+	if err := m.store.SaveAdvice(ctx, today, phase.Age, advice); err != nil {
+		slog.Warn("failed to cache moon advice", "age", phase.Age, logger.Err(err))
+	}
+
+	return advice, nil
 }
@@ -0,0 +1,55 @@
+package report
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/sushkevichd/day-guide-telegram-bot/pkg/domain"
+)
+
+type ChatFetcher interface {
+	FetchByID(ctx context.Context, chatID int64) (*domain.Chat, error)
+}
+
+type CurrentWeatherGenerator interface {
+	Generate(ctx context.Context, chatID int64) (string, error)
+}
+
+type ForecastGenerator interface {
+	Generate(ctx context.Context, chatID int64, city string, days int) (string, error)
+}
+
+type morningWeather struct {
+	chats    ChatFetcher
+	current  CurrentWeatherGenerator
+	forecast ForecastGenerator
+}
+
+// NewMorningWeather creates the generator of the weather broadcast, which reports the forecast for the day instead of
+// the current weather to the chats enabling it with /settings forecast on
+func NewMorningWeather(
+	chats ChatFetcher,
+	current CurrentWeatherGenerator,
+	forecast ForecastGenerator,
+) *morningWeather {
+	return &morningWeather{
+		chats:    chats,
+		current:  current,
+		forecast: forecast,
+	}
+}
+
+// Generate ignores now, the forecast is for the local date of the location already
+func (m *morningWeather) Generate(ctx context.Context, chatID int64, _ time.Time) (string, error) {
+	chat, err := m.chats.FetchByID(ctx, chatID)
+	if err != nil {
+		return "", fmt.Errorf("fetching chat %d: %v", chatID, err)
+	}
+
+	if !chat.WeatherForecast {
+		return m.current.Generate(ctx, chatID)
+	}
+
+	return m.forecast.Generate(ctx, chatID, "", 1)
+}
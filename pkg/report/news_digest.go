@@ -0,0 +1,226 @@
+package report
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sushkevichd/day-guide-telegram-bot/pkg/domain"
+	"github.com/sushkevichd/day-guide-telegram-bot/pkg/logger"
+)
+
+const (
+	// newsDigestSize is the maximal number of new stories in a digest
+	newsDigestSize = 10
+	// Stories of previous digests still ranked this high are listed again with the score they gained
+	newsTrendingRank = 10
+	// newsFrontPageSize is the number of the top stories summarized for all chats, as on the Hacker News front page
+	newsFrontPageSize = 30
+
+	newsSummaryPrompt = "Перескажи каждую историю одним предложением на русском языке. Ответь только строками вида " +
+		"`<id>: <пересказ>`, по одной на историю."
+)
+
+// newsSummaryLine is a line of the model's response, "<id>: <summary>"
+var newsSummaryLine = regexp.MustCompile(`^\W*(?i:id)?\s*(\d+)\W*:\s*(.+)$`)
+
+type NewsFetcher interface {
+	GetNews(ctx context.Context, list domain.NewsList, limit int) ([]domain.NewsItem, error)
+}
+
+type NewsSummarizer interface {
+	GenerateResponse(ctx context.Context, messages []domain.GMessage) (domain.GMessage, error)
+}
+
+type SentNewsStore interface {
+	FetchSentScores(ctx context.Context, chatID int64, itemIDs []string) (map[string]int, error)
+	SaveSent(ctx context.Context, chatID int64, items []domain.NewsItem) error
+}
+
+type newsDigest struct {
+	fetcher    NewsFetcher
+	summarizer NewsSummarizer
+	store      SentNewsStore
+
+	// summariesMu is held while summarizing, so that the front page is summarized once also for concurrent digests
+	summariesMu sync.Mutex
+	// summaries of the front page stories by ID, a story is summarized once while it is on the front page
+	summaries map[string]string
+
+	pendingMu sync.Mutex
+	// pending are the stories of the digests generated and not delivered yet by chat
+	pending map[int64][]domain.NewsItem
+}
+
+func NewNewsDigest(
+	fetcher NewsFetcher,
+	summarizer NewsSummarizer,
+	store SentNewsStore,
+) *newsDigest {
+	return &newsDigest{
+		fetcher:    fetcher,
+		summarizer: summarizer,
+		store:      store,
+		summaries:  make(map[string]string),
+		pending:    make(map[int64][]domain.NewsItem),
+	}
+}
+
+// Generate lists the top stories not sent to the chat before with their summaries and the sent ones still trending
+// with their score delta. The stories are recorded as sent by RecordDelivery, so the next digest continues from the
+// delivered one.
+func (n *newsDigest) Generate(ctx context.Context, chatID int64, _ time.Time) (string, error) {
+	items, err := n.fetcher.GetNews(ctx, domain.NewsTop, 0)
+	if err != nil {
+		return "", fmt.Errorf("fetching news: %v", err)
+	}
+
+	ids := make([]string, 0, len(items))
+	for _, item := range items {
+		ids = append(ids, item.ID)
+	}
+
+	sentScores, err := n.store.FetchSentScores(ctx, chatID, ids)
+	if err != nil {
+		return "", fmt.Errorf("fetching sent news: %v", err)
+	}
+
+	var fresh, trending []domain.NewsItem
+	for _, item := range items {
+		score, sent := sentScores[item.ID]
+		switch {
+		case !sent && len(fresh) < newsDigestSize:
+			fresh = append(fresh, item)
+		case sent && item.Rank <= newsTrendingRank && item.Score > score:
+			trending = append(trending, item)
+		}
+	}
+
+	n.pendingMu.Lock()
+	n.pending[chatID] = append(fresh, trending...)
+	n.pendingMu.Unlock()
+
+	if len(fresh) == 0 && len(trending) == 0 {
+		return "На Hacker News нет новых историй с прошлого выпуска.", nil
+	}
+
+	summaries := n.summarize(ctx, items[:min(len(items), newsFrontPageSize)])
+
+	var sb strings.Builder
+	sb.WriteString("📰 **Hacker News**\n\n")
+	for _, item := range fresh {
+		sb.WriteString(fmt.Sprintf("%d. [%s](%s) - %d points, %d comments\n", item.Rank, item.Title, item.URL, item.Score, item.Comments))
+		if summary, ok := summaries[item.ID]; ok {
+			sb.WriteString(summary + "\n")
+		}
+		sb.WriteString("\n")
+	}
+	if len(trending) > 0 {
+		sb.WriteString("📈 **Всё ещё в топе**\n\n")
+		for _, item := range trending {
+			sb.WriteString(fmt.Sprintf("- [%s](%s): %d (+%d)\n", item.Title, item.URL, item.Score, item.Score-sentScores[item.ID]))
+		}
+	}
+
+	return strings.TrimSpace(sb.String()), nil
+}
+
+// RecordDelivery records the stories of the chat's last digest as sent
+func (n *newsDigest) RecordDelivery(ctx context.Context, chatID int64) error {
+	n.pendingMu.Lock()
+	items := n.pending[chatID]
+	delete(n.pending, chatID)
+	n.pendingMu.Unlock()
+
+	if len(items) == 0 {
+		return nil
+	}
+
+	if err := n.store.SaveSent(ctx, chatID, items); err != nil {
+		return fmt.Errorf("saving sent news: %v", err)
+	}
+
+	return nil
+}
+
+// summarize returns the summaries of the front page stories by ID. Only the stories new on the front page are sent to
+// the model, so a broadcast pass summarizes the front page once for all chats. The stories the model failed to
+// summarize are listed without a summary.
+func (n *newsDigest) summarize(ctx context.Context, frontPage []domain.NewsItem) map[string]string {
+	n.summariesMu.Lock()
+	defer n.summariesMu.Unlock()
+
+	summaries := make(map[string]string, len(frontPage))
+	var missing []domain.NewsItem
+	for _, item := range frontPage {
+		if summary, ok := n.summaries[item.ID]; ok {
+			summaries[item.ID] = summary
+		} else {
+			missing = append(missing, item)
+		}
+	}
+
+	if len(missing) > 0 {
+		generated, err := n.generateSummaries(ctx, missing)
+		if err != nil {
+			slog.Error("failed to summarize news", logger.Err(err))
+		}
+		for id, summary := range generated {
+			summaries[id] = summary
+		}
+	}
+
+	// The stories off the front page are not shown anymore
+	n.summaries = summaries
+
+	return summaries
+}
+
+func (n *newsDigest) generateSummaries(ctx context.Context, items []domain.NewsItem) (map[string]string, error) {
+	var text strings.Builder
+	for _, item := range items {
+		text.WriteString(fmt.Sprintf("id %s\n%s\n%s\n\n", item.ID, item.ToText(), item.URL))
+	}
+
+	resp, err := n.summarizer.GenerateResponse(ctx, []domain.GMessage{
+		{
+			Role: "user",
+			Parts: []domain.GMessagePart{
+				{
+					Text: newsSummaryPrompt,
+				},
+				{
+					Text: text.String(),
+				},
+			},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	requested := make(map[string]bool, len(items))
+	for _, item := range items {
+		requested[item.ID] = true
+	}
+
+	summaries := make(map[string]string, len(items))
+	for _, p := range resp.Parts {
+		for _, line := range strings.Split(p.Text, "\n") {
+			m := newsSummaryLine.FindStringSubmatch(strings.TrimSpace(line))
+			if m != nil && requested[m[1]] {
+				summaries[m[1]] = strings.TrimSpace(m[2])
+			}
+		}
+	}
+	if len(summaries) == 0 {
+		return nil, errors.New("no summaries in response")
+	}
+
+	return summaries, nil
+}
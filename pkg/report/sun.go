@@ -0,0 +1,85 @@
+package report
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/sushkevichd/day-guide-telegram-bot/pkg/domain"
+	"github.com/sushkevichd/day-guide-telegram-bot/pkg/logger"
+	"github.com/sushkevichd/day-guide-telegram-bot/pkg/repository"
+)
+
+// Day length is compared with the weather saved a week ago, any poll of that day will do
+const (
+	sunComparisonAgo    = 7 * 24 * time.Hour
+	sunComparisonWindow = 12 * time.Hour
+)
+
+type SunFetcher interface {
+	FetchLatestByLocation(ctx context.Context, location domain.Location) (*domain.Weather, error)
+	FetchClosestByLocation(ctx context.Context, location domain.Location, ago, window time.Duration) (*domain.Weather, error)
+}
+
+type SunFormatter interface {
+	Format(w domain.Weather, weekAgo *domain.Weather) string
+}
+
+type sun struct {
+	locationFetcher LocationFetcher
+	fetcher         SunFetcher
+	formatter       SunFormatter
+}
+
+func NewSun(
+	locationFetcher LocationFetcher,
+	fetcher SunFetcher,
+	formatter SunFormatter,
+) *sun {
+	return &sun{
+		locationFetcher: locationFetcher,
+		fetcher:         fetcher,
+		formatter:       formatter,
+	}
+}
+
+// Generate reports sunrise, sunset and day length for the chat location named city, or for all locations of the chat
+// when city is empty
+func (s *sun) Generate(ctx context.Context, chatID int64, city string) (string, error) {
+	locations, err := s.locationFetcher.FetchByChatID(ctx, chatID)
+	if err != nil {
+		return "", fmt.Errorf("fetching locations for chat %d: %v", chatID, err)
+	}
+
+	if len(locations) == 0 {
+		return "Не выбрано ни одного города. Добавьте город командой /weather add <город>", nil
+	}
+
+	locations = locationsByName(locations, city)
+	if len(locations) == 0 {
+		return fmt.Sprintf("Город %s не добавлен. Добавьте его командой /weather add %s", city, city), nil
+	}
+
+	var sb strings.Builder
+	for _, loc := range locations {
+		weather, err := s.fetcher.FetchLatestByLocation(ctx, loc)
+		if err != nil {
+			slog.Warn("fetching latest weather", "location", loc.Name, logger.Err(err))
+			sb.WriteString(fmt.Sprintf("❓ %s - нет данных\n\n", loc.Name))
+			continue
+		}
+
+		weekAgo, err := s.fetcher.FetchClosestByLocation(ctx, loc, sunComparisonAgo, sunComparisonWindow)
+		if err != nil && !errors.Is(err, repository.ErrWeatherNotFound) {
+			slog.Warn("fetching weather a week ago", "location", loc.Name, logger.Err(err))
+		}
+
+		sb.WriteString(s.formatter.Format(*weather, weekAgo))
+		sb.WriteString("\n")
+	}
+
+	return sb.String(), nil
+}
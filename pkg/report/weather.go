@@ -3,48 +3,101 @@ package report
 import (
 	"context"
 	"fmt"
+	"log/slog"
 	"strings"
 
 	"github.com/sushkevichd/day-guide-telegram-bot/pkg/domain"
+	"github.com/sushkevichd/day-guide-telegram-bot/pkg/logger"
 )
 
 type WeatherFetcher interface {
 	FetchLatestByLocation(context.Context, domain.Location) (*domain.Weather, error)
 }
 
+type LocationFetcher interface {
+	FetchByChatID(ctx context.Context, chatID int64) ([]domain.Location, error)
+}
+
 type WeatherFormatter interface {
 	Format(weather domain.Weather) string
 }
 
 type weather struct {
-	locations []domain.Location
-	fetcher   WeatherFetcher
-	formatter WeatherFormatter
+	locationFetcher LocationFetcher
+	fetcher         WeatherFetcher
+	formatter       WeatherFormatter
 }
 
 func NewWeather(
-	locations []domain.Location,
+	locationFetcher LocationFetcher,
 	fetcher WeatherFetcher,
 	formatter WeatherFormatter,
 ) *weather {
 	return &weather{
-		locations: locations,
-		fetcher:   fetcher,
-		formatter: formatter,
+		locationFetcher: locationFetcher,
+		fetcher:         fetcher,
+		formatter:       formatter,
 	}
 }
 
-func (w *weather) Generate(ctx context.Context) (string, error) {
+func (w *weather) Generate(ctx context.Context, chatID int64) (string, error) {
+	locations, err := w.locationFetcher.FetchByChatID(ctx, chatID)
+	if err != nil {
+		return "", fmt.Errorf("fetching locations for chat %d: %v", chatID, err)
+	}
+
+	if len(locations) == 0 {
+		return "Не выбрано ни одного города. Добавьте город командой /weather add <город>", nil
+	}
+
+	return w.generate(ctx, locations), nil
+}
+
+// GenerateForLocation generates the report for a single location of the chat picked by ID
+func (w *weather) GenerateForLocation(ctx context.Context, chatID int64, locationID int64) (string, error) {
+	locations, err := w.locationFetcher.FetchByChatID(ctx, chatID)
+	if err != nil {
+		return "", fmt.Errorf("fetching locations for chat %d: %v", chatID, err)
+	}
+
+	for _, loc := range locations {
+		if loc.ID == locationID {
+			return w.generate(ctx, []domain.Location{loc}), nil
+		}
+	}
+
+	return "", fmt.Errorf("location %d is not configured for chat %d", locationID, chatID)
+}
+
+func (w *weather) generate(ctx context.Context, locations []domain.Location) string {
 	var sb strings.Builder
-	for _, loc := range w.locations {
+	for _, loc := range locations {
 		weather, err := w.fetcher.FetchLatestByLocation(ctx, loc)
 		if err != nil {
-			return "", fmt.Errorf("fetching latest weather for location %s: %v", loc, err)
+			// A freshly added location has no data until the next loader pass
+			slog.Warn("fetching latest weather", "location", loc.Name, logger.Err(err))
+			sb.WriteString(fmt.Sprintf("❓ %s - нет данных\n\n", loc.Name))
+			continue
 		}
 
 		sb.WriteString(w.formatter.Format(*weather))
 		sb.WriteString("\n")
 	}
 
-	return sb.String(), nil
+	return sb.String()
+}
+
+// locationsByName returns the locations named city in any case, or all of them when city is empty
+func locationsByName(locations []domain.Location, city string) []domain.Location {
+	if city == "" {
+		return locations
+	}
+
+	var selected []domain.Location
+	for _, loc := range locations {
+		if strings.EqualFold(loc.Name, city) {
+			selected = append(selected, loc)
+		}
+	}
+	return selected
 }
@@ -0,0 +1,122 @@
+package report
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/wcharczuk/go-chart/v2"
+	"github.com/wcharczuk/go-chart/v2/drawing"
+
+	"github.com/sushkevichd/day-guide-telegram-bot/pkg/domain"
+	"github.com/sushkevichd/day-guide-telegram-bot/pkg/formatter"
+)
+
+var (
+	// ErrNotEnoughWeatherHistory is returned when there are too few polls to draw a line
+	ErrNotEnoughWeatherHistory = errors.New("not enough weather history")
+	// ErrLocationNotAdded is returned for a city which is not among the chat's locations
+	ErrLocationNotAdded = errors.New("location is not added to the chat")
+)
+
+type WeatherHistoryFetcher interface {
+	FetchHistoryByLocation(ctx context.Context, location domain.Location, days int) ([]domain.Weather, error)
+}
+
+type WeatherSummaryFormatter interface {
+	Format(summary domain.WeatherSummary, days int) string
+}
+
+type weatherPlot struct {
+	locationFetcher LocationFetcher
+	fetcher         WeatherHistoryFetcher
+	formatter       WeatherSummaryFormatter
+}
+
+func NewWeatherPlot(
+	locationFetcher LocationFetcher,
+	fetcher WeatherHistoryFetcher,
+	formatter WeatherSummaryFormatter,
+) *weatherPlot {
+	return &weatherPlot{
+		locationFetcher: locationFetcher,
+		fetcher:         fetcher,
+		formatter:       formatter,
+	}
+}
+
+// Generate renders the temperature, feels-like and pressure of the chat location named city over the given number of
+// days and the caption with their summary
+func (w *weatherPlot) Generate(ctx context.Context, chatID int64, city string, days int) ([]byte, string, error) {
+	locations, err := w.locationFetcher.FetchByChatID(ctx, chatID)
+	if err != nil {
+		return nil, "", fmt.Errorf("fetching locations for chat %d: %v", chatID, err)
+	}
+
+	locations = locationsByName(locations, city)
+	if city == "" || len(locations) == 0 {
+		return nil, "", fmt.Errorf("%w: %s", ErrLocationNotAdded, city)
+	}
+	loc := locations[0]
+
+	history, err := w.fetcher.FetchHistoryByLocation(ctx, loc, days)
+	if err != nil {
+		return nil, "", fmt.Errorf("fetching weather history for %s: %v", loc.Name, err)
+	}
+	if len(history) < 2 {
+		return nil, "", fmt.Errorf("%w for %s", ErrNotEnoughWeatherHistory, loc.Name)
+	}
+
+	var xValues []time.Time
+	var temp, tempFeel, pressure []float64
+	for _, h := range history {
+		xValues = append(xValues, h.Timestamp)
+		temp = append(temp, h.Temp)
+		tempFeel = append(tempFeel, h.TempFeel)
+		pressure = append(pressure, float64(formatter.HPaToMmHg(h.Pressure)))
+	}
+
+	graph := chart.Chart{
+		Height: 700,
+		Width:  1024,
+		Series: []chart.Series{
+			chart.TimeSeries{
+				Name:    "Температура, °C",
+				XValues: xValues,
+				YValues: temp,
+				Style: chart.Style{
+					StrokeColor: drawing.ColorRed,
+					StrokeWidth: 2,
+				},
+			},
+			chart.TimeSeries{
+				Name:    "Ощущается, °C",
+				XValues: xValues,
+				YValues: tempFeel,
+				Style: chart.Style{
+					StrokeColor:     drawing.ColorRed.WithAlpha(128),
+					StrokeDashArray: []float64{5, 5},
+				},
+			},
+			chart.TimeSeries{
+				Name:    "Давление, мм",
+				XValues: xValues,
+				YValues: pressure,
+				YAxis:   chart.YAxisSecondary,
+				Style: chart.Style{
+					StrokeColor: drawing.ColorBlue,
+				},
+			},
+		},
+	}
+	graph.Elements = []chart.Renderable{chart.Legend(&graph)}
+
+	buffer := bytes.NewBuffer([]byte{})
+	if err := graph.Render(chart.PNG, buffer); err != nil {
+		return nil, "", fmt.Errorf("rendering weather chart for %s: %v", loc.Name, err)
+	}
+
+	return buffer.Bytes(), w.formatter.Format(*domain.SummarizeWeather(history), days), nil
+}
@@ -0,0 +1,59 @@
+package report
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/sushkevichd/day-guide-telegram-bot/pkg/domain"
+	"github.com/sushkevichd/day-guide-telegram-bot/pkg/logger"
+)
+
+const weeklyWeatherDays = 7
+
+type weeklyWeather struct {
+	locationFetcher LocationFetcher
+	fetcher         WeatherHistoryFetcher
+	formatter       WeatherSummaryFormatter
+}
+
+func NewWeeklyWeather(
+	locationFetcher LocationFetcher,
+	fetcher WeatherHistoryFetcher,
+	formatter WeatherSummaryFormatter,
+) *weeklyWeather {
+	return &weeklyWeather{
+		locationFetcher: locationFetcher,
+		fetcher:         fetcher,
+		formatter:       formatter,
+	}
+}
+
+// Generate summarizes the weather of the past week in every location of the chat
+func (w *weeklyWeather) Generate(ctx context.Context, chatID int64, _ time.Time) (string, error) {
+	locations, err := w.locationFetcher.FetchByChatID(ctx, chatID)
+	if err != nil {
+		return "", fmt.Errorf("fetching locations for chat %d: %v", chatID, err)
+	}
+
+	if len(locations) == 0 {
+		return "Не выбрано ни одного города. Добавьте город командой /weather add <город>", nil
+	}
+
+	var sb strings.Builder
+	for _, loc := range locations {
+		history, err := w.fetcher.FetchHistoryByLocation(ctx, loc, weeklyWeatherDays)
+		if err != nil || len(history) == 0 {
+			slog.Warn("fetching weather history", "location", loc.Name, logger.Err(err))
+			sb.WriteString(fmt.Sprintf("❓ %s - нет данных\n\n", loc.Name))
+			continue
+		}
+
+		sb.WriteString(w.formatter.Format(*domain.SummarizeWeather(history), weeklyWeatherDays))
+		sb.WriteString("\n")
+	}
+
+	return sb.String(), nil
+}
@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/sushkevichd/day-guide-telegram-bot/pkg/domain"
+	"github.com/sushkevichd/day-guide-telegram-bot/pkg/logger"
+)
+
+var ErrAlertNotFound = errors.New("alert not found")
+
+type alertRepository struct {
+	db *sql.DB
+}
+
+func NewAlertRepository(db *sql.DB) *alertRepository {
+	return &alertRepository{db: db}
+}
+
+func (repo *alertRepository) Add(ctx context.Context, a domain.ExchangeRateAlert) (int64, error) {
+	q := `
+		insert into alerts (chat_id, base, quote, condition, threshold, triggered, reference_rate)
+		values ($1, $2, $3, $4, $5, $6, $7)
+		returning id
+	`
+
+	var id int64
+	if err := repo.db.QueryRowContext(ctx, q,
+		a.ChatID, a.Pair.Base, a.Pair.Quote, a.Condition, a.Threshold, a.Triggered, a.ReferenceRate,
+	).Scan(&id); err != nil {
+		if pgErrorCode(err) == pgForeignKeyViolation {
+			return 0, ErrChatNotRegistered
+		}
+		return 0, fmt.Errorf("adding alert: %v", err)
+	}
+
+	return id, nil
+}
+
+func (repo *alertRepository) Remove(ctx context.Context, chatID, id int64) error {
+	q := `delete from alerts where chat_id = $1 and id = $2`
+
+	res, err := repo.db.ExecContext(ctx, q, chatID, id)
+	if err != nil {
+		return fmt.Errorf("removing alert: %v", err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("getting affected rows: %v", err)
+	}
+	if affected == 0 {
+		return ErrAlertNotFound
+	}
+
+	return nil
+}
+
+func (repo *alertRepository) FetchByChatID(ctx context.Context, chatID int64) ([]domain.ExchangeRateAlert, error) {
+	q := `
+		select id, chat_id, base, quote, condition, threshold, triggered, reference_rate
+		from alerts
+		where chat_id = $1
+		order by id
+	`
+
+	return repo.fetch(ctx, q, chatID)
+}
+
+func (repo *alertRepository) FetchAll(ctx context.Context) ([]domain.ExchangeRateAlert, error) {
+	q := `
+		select id, chat_id, base, quote, condition, threshold, triggered, reference_rate
+		from alerts
+		order by id
+	`
+
+	return repo.fetch(ctx, q)
+}
+
+// UpdateState stores the de-duplication state of an evaluated alert
+func (repo *alertRepository) UpdateState(ctx context.Context, a domain.ExchangeRateAlert) error {
+	q := `update alerts set triggered = $2, reference_rate = $3 where id = $1`
+
+	if _, err := repo.db.ExecContext(ctx, q, a.ID, a.Triggered, a.ReferenceRate); err != nil {
+		return fmt.Errorf("updating alert state: %v", err)
+	}
+
+	return nil
+}
+
+func (repo *alertRepository) fetch(ctx context.Context, q string, args ...any) ([]domain.ExchangeRateAlert, error) {
+	rows, err := repo.db.QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, fmt.Errorf("querying alerts: %v", err)
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			slog.Warn("Failed to close rows", logger.Err(err))
+		}
+	}()
+
+	var alerts []domain.ExchangeRateAlert
+	for rows.Next() {
+		var a domain.ExchangeRateAlert
+		if err := rows.Scan(
+			&a.ID,
+			&a.ChatID,
+			&a.Pair.Base,
+			&a.Pair.Quote,
+			&a.Condition,
+			&a.Threshold,
+			&a.Triggered,
+			&a.ReferenceRate,
+		); err != nil {
+			return nil, fmt.Errorf("scanning rows: %v", err)
+		}
+		alerts = append(alerts, a)
+	}
+
+	return alerts, rows.Err()
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"log/slog"
 	"strings"
+	"time"
 
 	"github.com/sushkevichd/day-guide-telegram-bot/pkg/domain"
 	"github.com/sushkevichd/day-guide-telegram-bot/pkg/logger"
@@ -59,3 +60,78 @@ func (repo *chatRepository) GetIDs(ctx context.Context) ([]int64, error) {
 
 	return ids, rows.Err()
 }
+
+func (repo *chatRepository) SetTimezone(ctx context.Context, chatID int64, timezone string) error {
+	q := `update chats set timezone = $2 where id = $1`
+
+	res, err := repo.db.ExecContext(ctx, q, chatID, timezone)
+	if err != nil {
+		return fmt.Errorf("updating timezone: %v", err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("getting affected rows: %v", err)
+	}
+	if affected == 0 {
+		return ErrChatNotRegistered
+	}
+
+	return nil
+}
+
+func (repo *chatRepository) SetWeatherForecast(ctx context.Context, chatID int64, enabled bool) error {
+	q := `update chats set weather_forecast = $2 where id = $1`
+
+	res, err := repo.db.ExecContext(ctx, q, chatID, enabled)
+	if err != nil {
+		return fmt.Errorf("updating weather forecast: %v", err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("getting affected rows: %v", err)
+	}
+	if affected == 0 {
+		return ErrChatNotRegistered
+	}
+
+	return nil
+}
+
+// FetchLocation returns the chat's timezone, the default one for chats not registered yet
+func (repo *chatRepository) FetchLocation(ctx context.Context, chatID int64) (*time.Location, error) {
+	timezone := domain.DefaultTimezone
+
+	err := repo.db.QueryRowContext(ctx, `select timezone from chats where id = $1`, chatID).Scan(&timezone)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("scanning row: %v", err)
+	}
+
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		return nil, fmt.Errorf("loading timezone %s: %v", timezone, err)
+	}
+
+	return loc, nil
+}
+
+func (repo *chatRepository) FetchByID(ctx context.Context, chatID int64) (*domain.Chat, error) {
+	q := `select id, registered_by, registered_at, timezone, weather_forecast from chats where id = $1`
+
+	var chat domain.Chat
+	if err := repo.db.QueryRowContext(ctx, q, chatID).Scan(
+		&chat.ID,
+		&chat.RegisteredBy,
+		&chat.RegisteredAt,
+		&chat.Timezone,
+		&chat.WeatherForecast,
+	); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrChatNotRegistered
+		}
+		return nil, fmt.Errorf("scanning row: %v", err)
+	}
+
+	return &chat, nil
+}
@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/sushkevichd/day-guide-telegram-bot/pkg/domain"
+	"github.com/sushkevichd/day-guide-telegram-bot/pkg/logger"
+)
+
+var ErrChatEventNotFound = errors.New("chat event not found")
+
+type chatEventRepository struct {
+	db *sql.DB
+}
+
+func NewChatEventRepository(db *sql.DB) *chatEventRepository {
+	return &chatEventRepository{db: db}
+}
+
+func (repo *chatEventRepository) Add(ctx context.Context, e domain.ChatEvent) (int64, error) {
+	q := `
+		insert into chat_events (chat_id, name, month, day, year, yearly, remind_days)
+		values ($1, $2, $3, $4, $5, $6, $7)
+		returning id
+	`
+
+	var year sql.NullInt64
+	if e.Year != 0 {
+		year = sql.NullInt64{Int64: int64(e.Year), Valid: true}
+	}
+
+	var id int64
+	if err := repo.db.QueryRowContext(ctx, q,
+		e.ChatID, e.Name, int(e.Month), e.Day, year, e.Yearly, e.RemindDays,
+	).Scan(&id); err != nil {
+		if pgErrorCode(err) == pgForeignKeyViolation {
+			return 0, ErrChatNotRegistered
+		}
+		return 0, fmt.Errorf("adding chat event: %v", err)
+	}
+
+	return id, nil
+}
+
+func (repo *chatEventRepository) Remove(ctx context.Context, chatID, id int64) error {
+	q := `delete from chat_events where chat_id = $1 and id = $2`
+
+	res, err := repo.db.ExecContext(ctx, q, chatID, id)
+	if err != nil {
+		return fmt.Errorf("removing chat event: %v", err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("getting affected rows: %v", err)
+	}
+	if affected == 0 {
+		return ErrChatEventNotFound
+	}
+
+	return nil
+}
+
+func (repo *chatEventRepository) FetchByChatID(ctx context.Context, chatID int64) ([]domain.ChatEvent, error) {
+	q := `
+		select id, chat_id, name, month, day, year, yearly, remind_days
+		from chat_events
+		where chat_id = $1
+		order by month, day, id
+	`
+
+	rows, err := repo.db.QueryContext(ctx, q, chatID)
+	if err != nil {
+		return nil, fmt.Errorf("querying chat events: %v", err)
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			slog.Warn("Failed to close rows", logger.Err(err))
+		}
+	}()
+
+	var events []domain.ChatEvent
+	for rows.Next() {
+		var e domain.ChatEvent
+		var month int
+		var year sql.NullInt64
+		if err := rows.Scan(&e.ID, &e.ChatID, &e.Name, &month, &e.Day, &year, &e.Yearly, &e.RemindDays); err != nil {
+			return nil, fmt.Errorf("scanning rows: %v", err)
+		}
+		e.Month = time.Month(month)
+		e.Year = int(year.Int64)
+		events = append(events, e)
+	}
+
+	return events, rows.Err()
+}
@@ -3,15 +3,21 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
-	"strings"
 	"time"
 
 	"github.com/sushkevichd/day-guide-telegram-bot/pkg/domain"
 	"github.com/sushkevichd/day-guide-telegram-bot/pkg/logger"
 )
 
+var ErrNoExchangeRates = errors.New("no exchange rates loaded yet")
+
+// crossRate is the SQL expression of the quote per base rate of a snapshot, $1 is base and $2 is quote
+const crossRate = `((rates->>$2)::float8 / (rates->>$1)::float8)`
+
 type exchangeRateRepository struct {
 	db *sql.DB
 }
@@ -20,51 +26,70 @@ func NewExchangeRateRepository(db *sql.DB) *exchangeRateRepository {
 	return &exchangeRateRepository{db: db}
 }
 
-func (repo *exchangeRateRepository) Save(ctx context.Context, e *domain.ExchangeRate) error {
-	columns := []string{"base", "quote", "rate"}
-	args := []any{e.Pair.Base, e.Pair.Quote, e.Rate}
+func (repo *exchangeRateRepository) Save(ctx context.Context, s *domain.ExchangeRateSnapshot) error {
+	rates := make(map[string]float64, len(s.Rates)+1)
+	for currency, rate := range s.Rates {
+		rates[currency] = rate
+	}
+	// The base is stored explicitly so that cross rates can be computed in SQL for any pair
+	rates[s.Base.String()] = 1
 
-	placeholders := make([]string, len(columns))
-	for i := range columns {
-		placeholders[i] = fmt.Sprintf("$%d", i+1)
+	ratesJSON, err := json.Marshal(rates)
+	if err != nil {
+		return fmt.Errorf("marshaling rates: %v", err)
 	}
 
-	q := `insert into exchange_rates (` + strings.Join(columns, ", ") + `) values (` + strings.Join(placeholders, ",") + `)`
+	q := `insert into exchange_rate_snapshots (base, rates) values ($1, $2)`
 
-	if _, err := repo.db.ExecContext(ctx, q, args...); err != nil {
+	if _, err := repo.db.ExecContext(ctx, q, s.Base, string(ratesJSON)); err != nil {
 		return fmt.Errorf("executing query: %v", err)
 	}
 
 	return nil
 }
 
-func (repo *exchangeRateRepository) FetchLatestRate(ctx context.Context, pair domain.CurrencyPair) (*domain.ExchangeRate, error) {
+func (repo *exchangeRateRepository) FetchLatestSnapshot(ctx context.Context) (*domain.ExchangeRateSnapshot, error) {
 	q := `
-		select rate
-		from exchange_rates
-		where base = $1
-		and quote = $2
-		order by created_at desc
+		select created_at, base, rates
+		from exchange_rate_snapshots
+		order by created_at desc, id desc
 		limit 1;
 	`
 
-	e := domain.ExchangeRate{Pair: pair}
-	if err := repo.db.QueryRowContext(ctx, q, pair.Base, pair.Quote).Scan(
-		&e.Rate,
+	var s domain.ExchangeRateSnapshot
+	var ratesJSON []byte
+	if err := repo.db.QueryRowContext(ctx, q).Scan(
+		&s.Timestamp,
+		&s.Base,
+		&ratesJSON,
 	); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNoExchangeRates
+		}
 		return nil, fmt.Errorf("scanning row: %v", err)
 	}
 
-	return &e, nil
+	if err := json.Unmarshal(ratesJSON, &s.Rates); err != nil {
+		return nil, fmt.Errorf("unmarshaling rates: %v", err)
+	}
+
+	return &s, nil
+}
+
+func (repo *exchangeRateRepository) FetchLatestRate(ctx context.Context, pair domain.CurrencyPair) (*domain.ExchangeRate, error) {
+	snapshot, err := repo.FetchLatestSnapshot(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return snapshot.Rate(pair)
 }
 
 func (repo *exchangeRateRepository) FetchAverageRateForDay(ctx context.Context, pair domain.CurrencyPair, date time.Time) (*domain.ExchangeRate, error) {
 	q := `
-		select coalesce(avg(rate),0)
-		from exchange_rates
-		where base = $1
-		and quote = $2
-		and date_trunc('day', created_at) = $3
+		select coalesce(avg(` + crossRate + `),0)
+		from exchange_rate_snapshots
+		where date_trunc('day', created_at) = $3
 	`
 
 	e := domain.ExchangeRate{Pair: pair}
@@ -80,35 +105,37 @@ func (repo *exchangeRateRepository) FetchAverageRateForDay(ctx context.Context,
 
 func (repo *exchangeRateRepository) FetchHistoryRate(ctx context.Context, pair domain.CurrencyPair, days int) ([]domain.ExchangeRate, error) {
 	q := `
-		WITH LastRateToday AS (
+		WITH Rates AS (
+			SELECT created_at,
+			       ` + crossRate + ` AS rate
+			FROM exchange_rate_snapshots
+			WHERE rates->>$1 IS NOT NULL
+			  AND rates->>$2 IS NOT NULL
+		),
+		LastRateToday AS (
 			SELECT date_trunc('day', created_at) AS date,
 				   rate
-			FROM exchange_rates
-			WHERE base = $1
-			  AND quote = $2
-			  AND date_trunc('day', created_at) = date_trunc('day', current_timestamp)
+			FROM Rates
+			WHERE date_trunc('day', created_at) = date_trunc('day', current_timestamp)
 			ORDER BY created_at DESC
 			LIMIT 1
 		),
 		RecentAvgRates AS (
 			SELECT date_trunc('day', created_at) AS date,
 				   avg(rate) AS rate
-			FROM exchange_rates
-			WHERE base = $3
-			  AND quote = $4
-			  AND date_trunc('day', created_at) != date_trunc('day', current_timestamp)
+			FROM Rates
+			WHERE date_trunc('day', created_at) != date_trunc('day', current_timestamp)
 			GROUP BY date_trunc('day', created_at)
 			ORDER BY date DESC
-			LIMIT $5
+			LIMIT $3
 		)
 		SELECT * FROM RecentAvgRates
 		UNION ALL
 		SELECT * FROM LastRateToday
 		ORDER BY date DESC;
-
 	`
 
-	rows, err := repo.db.QueryContext(ctx, q, pair.Base, pair.Quote, pair.Base, pair.Quote, days)
+	rows, err := repo.db.QueryContext(ctx, q, pair.Base, pair.Quote, days)
 	if err != nil {
 		return nil, fmt.Errorf("querying history rate: %v", err)
 	}
@@ -120,7 +147,7 @@ func (repo *exchangeRateRepository) FetchHistoryRate(ctx context.Context, pair d
 
 	var rates []domain.ExchangeRate
 	for rows.Next() {
-		var rate domain.ExchangeRate
+		rate := domain.ExchangeRate{Pair: pair}
 		var tsStr string
 		if err := rows.Scan(
 			&tsStr,
@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/sushkevichd/day-guide-telegram-bot/pkg/domain"
+	"github.com/sushkevichd/day-guide-telegram-bot/pkg/logger"
+)
+
+type forecastRepository struct {
+	db *sql.DB
+}
+
+func NewForecastRepository(db *sql.DB) *forecastRepository {
+	return &forecastRepository{db: db}
+}
+
+// Save replaces the stored forecast of every day covered by f
+func (repo *forecastRepository) Save(ctx context.Context, f *domain.Forecast) error {
+	q := `
+		insert into forecasts (
+			location, lat, lon, date, timezone_offset, temp_min, temp_max, precipitation_probability, wind_speed, weather,
+			weather_verbose
+		)
+		values ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		on conflict (lat, lon, date) do update set
+			location = excluded.location,
+			timezone_offset = excluded.timezone_offset,
+			temp_min = excluded.temp_min,
+			temp_max = excluded.temp_max,
+			precipitation_probability = excluded.precipitation_probability,
+			wind_speed = excluded.wind_speed,
+			weather = excluded.weather,
+			weather_verbose = excluded.weather_verbose,
+			updated_at = current_timestamp
+	`
+
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("beginning transaction: %v", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	for _, d := range f.Days {
+		if _, err := tx.ExecContext(ctx, q,
+			f.Location,
+			f.Lat,
+			f.Lon,
+			d.Date.Format(time.DateOnly),
+			f.TimezoneOffset,
+			d.TempMin,
+			d.TempMax,
+			d.PrecipitationProbability,
+			d.WindSpeed,
+			d.Weather,
+			d.WeatherVerbose,
+		); err != nil {
+			return fmt.Errorf("saving forecast for %s: %v", d.Date.Format(time.DateOnly), err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("committing transaction: %v", err)
+	}
+
+	return nil
+}
+
+// FetchByLocation returns the forecast for the given number of days starting from today in the location's timezone
+func (repo *forecastRepository) FetchByLocation(ctx context.Context, location domain.Location, days int) (*domain.Forecast, error) {
+	q := `
+		select date, timezone_offset, temp_min, temp_max, precipitation_probability, wind_speed, weather, weather_verbose
+		from forecasts
+		where lat = $1 and lon = $2
+		  and date >= (current_timestamp at time zone 'UTC' + timezone_offset * interval '1 second')::date
+		order by date
+		limit $3
+	`
+
+	rows, err := repo.db.QueryContext(ctx, q, location.Lat, location.Lon, days)
+	if err != nil {
+		return nil, fmt.Errorf("querying forecast: %v", err)
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			slog.Warn("Failed to close rows", logger.Err(err))
+		}
+	}()
+
+	f := domain.Forecast{Location: location.Name, Lat: location.Lat, Lon: location.Lon}
+	for rows.Next() {
+		var d domain.ForecastDay
+		var dateStr string
+		if err := rows.Scan(
+			&dateStr,
+			&f.TimezoneOffset,
+			&d.TempMin,
+			&d.TempMax,
+			&d.PrecipitationProbability,
+			&d.WindSpeed,
+			&d.Weather,
+			&d.WeatherVerbose,
+		); err != nil {
+			return nil, fmt.Errorf("scanning rows: %v", err)
+		}
+
+		date, err := time.Parse(time.DateOnly, dateStr)
+		if err != nil {
+			return nil, fmt.Errorf("parsing date: %v", err)
+		}
+		d.Date = date
+		f.Days = append(f.Days, d)
+	}
+
+	return &f, rows.Err()
+}
@@ -3,8 +3,11 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"log/slog"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/lib/pq"
@@ -21,23 +24,84 @@ func NewHolidayRepository(db *sql.DB) *holidayRepository {
 	return &holidayRepository{db: db}
 }
 
+const holidayColumns = `
+	h.order_number,
+	h.name,
+	h.rule,
+	h.month,
+	h.day,
+	h.weekday,
+	h.week,
+	h.day_offset,
+	COALESCE(ARRAY_AGG(c.id ORDER BY c.id) FILTER (WHERE c.id IS NOT NULL), '{}') AS category_ids,
+	COALESCE(ARRAY_AGG(c.name ORDER BY c.id) FILTER (WHERE c.id IS NOT NULL), '{}') AS category_names,
+	COALESCE(ARRAY_AGG(c.emoji ORDER BY c.id) FILTER (WHERE c.id IS NOT NULL), '{}') AS category_emojis
+`
+
+// FetchByDate resolves the recurrence of the holidays for the date. The candidates are the holidays of the month and
+// the ones which month is not the one they are celebrated in: relative to Easter, to the start of the year or shifted
+// from a weekday, e.g. the Cyber Monday may follow the 4th Thursday of November in December.
 func (repo *holidayRepository) FetchByDate(ctx context.Context, date time.Time) ([]domain.Holiday, error) {
+	candidates, err := repo.fetch(
+		ctx,
+		`h.month = $1 or h.rule = $2 or h.rule = $3 or h.day_offset <> 0`,
+		int(date.Month()), domain.HolidayRuleEaster, domain.HolidayRuleDayOfYear,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	var holidays []domain.Holiday
+	for _, holiday := range candidates {
+		if !holiday.Recurrence.Matches(date) {
+			continue
+		}
+		holiday.Date = date
+		holidays = append(holidays, holiday)
+	}
+
+	return holidays, nil
+}
+
+// SearchByName returns the holidays which name contains the text, dated by their next celebration since the date and
+// sorted by it
+func (repo *holidayRepository) SearchByName(ctx context.Context, text string, since time.Time) ([]domain.Holiday, error) {
+	pattern := "%" + strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(text) + "%"
+
+	candidates, err := repo.fetch(ctx, `h.name ilike $1`, pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	var holidays []domain.Holiday
+	for _, holiday := range candidates {
+		date, ok := holiday.Recurrence.NextDate(since)
+		if !ok {
+			continue
+		}
+		holiday.Date = date
+		holidays = append(holidays, holiday)
+	}
+
+	sort.SliceStable(holidays, func(i, j int) bool {
+		return holidays[i].Date.Before(holidays[j].Date)
+	})
+
+	return holidays, nil
+}
+
+func (repo *holidayRepository) fetch(ctx context.Context, where string, args ...any) ([]domain.Holiday, error) {
 	q := `
-        select h.order_number,
-               h.name,
-               ARRAY_AGG(c.name) AS categories
+		select ` + holidayColumns + `
 		from holidays h
-		join holiday_category_links hcl ON h.id = hcl.holiday_id
-		join holiday_categories c ON hcl.category_id = c.id
-		where h.date = $1
+		left join holiday_category_links hcl ON h.id = hcl.holiday_id
+		left join holiday_categories c ON hcl.category_id = c.id
+		where ` + where + `
 		group by h.id
-		order by h.order_number;
+		order by h.order_number, h.id;
 	`
 
-	// Format date to YYYY-MM-DD for comparison in SQL
-	dateFormatted := date.Format("2006-01-02")
-
-	rows, err := repo.db.QueryContext(ctx, q, dateFormatted)
+	rows, err := repo.db.QueryContext(ctx, q, args...)
 	if err != nil {
 		return nil, fmt.Errorf("querying holidays: %v", err)
 	}
@@ -51,20 +115,38 @@ func (repo *holidayRepository) FetchByDate(ctx context.Context, date time.Time)
 
 	for rows.Next() {
 		var holiday domain.Holiday
-		var categoryNames []string
+		var month, day, weekday, week, dayOffset sql.NullInt64
+		var categoryIDs []int64
+		var categoryNames, categoryEmojis []string
 
 		if err := rows.Scan(
 			&holiday.OrderNumber,
 			&holiday.Name,
+			&holiday.Recurrence.Rule,
+			&month,
+			&day,
+			&weekday,
+			&week,
+			&dayOffset,
+			pq.Array(&categoryIDs),
 			pq.Array(&categoryNames),
+			pq.Array(&categoryEmojis),
 		); err != nil {
 			return nil, fmt.Errorf("scanning rows: %v", err)
 		}
 
-		holiday.Date = date
+		holiday.Recurrence.Month = time.Month(month.Int64)
+		holiday.Recurrence.Day = int(day.Int64)
+		holiday.Recurrence.Weekday = time.Weekday(weekday.Int64)
+		holiday.Recurrence.Week = int(week.Int64)
+		holiday.Recurrence.DayOffset = int(dayOffset.Int64)
 
-		for _, categoryName := range categoryNames {
-			holiday.Categories = append(holiday.Categories, categoryName)
+		for i := range categoryIDs {
+			holiday.Categories = append(holiday.Categories, domain.HolidayCategory{
+				ID:    categoryIDs[i],
+				Name:  categoryNames[i],
+				Emoji: categoryEmojis[i],
+			})
 		}
 
 		holidays = append(holidays, holiday)
@@ -72,3 +154,108 @@ func (repo *holidayRepository) FetchByDate(ctx context.Context, date time.Time)
 
 	return holidays, rows.Err()
 }
+
+// Import upserts the holidays celebrated on the date. A holiday is matched by name with the one of the same day or with
+// a floating one, so importing a page again or a page of another year doesn't duplicate it.
+func (repo *holidayRepository) Import(ctx context.Context, date time.Time, holidays []domain.Holiday) (*domain.HolidayImportSummary, error) {
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("beginning transaction: %v", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	var summary domain.HolidayImportSummary
+	for _, h := range holidays {
+		id, inserted, updated, err := upsertHoliday(ctx, tx, date, h)
+		if err != nil {
+			return nil, fmt.Errorf("importing holiday %q: %v", h.Name, err)
+		}
+		if inserted {
+			summary.HolidaysInserted++
+		}
+		if updated {
+			summary.HolidaysUpdated++
+		}
+
+		for _, category := range h.Categories {
+			categoryID, inserted, err := upsertHolidayCategory(ctx, tx, category.Name)
+			if err != nil {
+				return nil, fmt.Errorf("importing category %q: %v", category.Name, err)
+			}
+			if inserted {
+				summary.CategoriesInserted++
+			}
+
+			res, err := tx.ExecContext(ctx, `
+				insert into holiday_category_links (holiday_id, category_id) values ($1, $2)
+				on conflict do nothing
+			`, id, categoryID)
+			if err != nil {
+				return nil, fmt.Errorf("linking holiday %q to category %q: %v", h.Name, category.Name, err)
+			}
+			affected, err := res.RowsAffected()
+			if err != nil {
+				return nil, fmt.Errorf("getting affected rows: %v", err)
+			}
+			summary.LinksInserted += int(affected)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("committing transaction: %v", err)
+	}
+
+	return &summary, nil
+}
+
+func upsertHoliday(ctx context.Context, tx *sql.Tx, date time.Time, h domain.Holiday) (id int64, inserted, updated bool, err error) {
+	q := `
+		select id from holidays
+		where name = $1 and (rule <> $2 or (month = $3 and day = $4))
+		order by id
+		limit 1
+	`
+
+	err = tx.QueryRowContext(ctx, q, h.Name, domain.HolidayRuleFixed, int(date.Month()), date.Day()).Scan(&id)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		q = `
+			insert into holidays (order_number, name, rule, month, day) values ($1, $2, $3, $4, $5)
+			returning id
+		`
+		if err := tx.QueryRowContext(ctx, q, h.OrderNumber, h.Name, domain.HolidayRuleFixed, int(date.Month()), date.Day()).Scan(&id); err != nil {
+			return 0, false, false, fmt.Errorf("inserting holiday: %v", err)
+		}
+		return id, true, false, nil
+	case err != nil:
+		return 0, false, false, fmt.Errorf("querying holiday: %v", err)
+	}
+
+	res, err := tx.ExecContext(ctx, `update holidays set order_number = $2 where id = $1 and order_number is distinct from $2`, id, h.OrderNumber)
+	if err != nil {
+		return 0, false, false, fmt.Errorf("updating holiday: %v", err)
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return 0, false, false, fmt.Errorf("getting affected rows: %v", err)
+	}
+
+	return id, false, affected > 0, nil
+}
+
+func upsertHolidayCategory(ctx context.Context, tx *sql.Tx, name string) (id int64, inserted bool, err error) {
+	// The no-op update makes the existing row returned, xmax is zero only for a freshly inserted one
+	q := `
+		insert into holiday_categories (name) values ($1)
+		on conflict (name) do update set name = excluded.name
+		returning id, xmax = 0
+	`
+
+	if err := tx.QueryRowContext(ctx, q, name).Scan(&id, &inserted); err != nil {
+		return 0, false, fmt.Errorf("upserting category: %v", err)
+	}
+
+	return id, inserted, nil
+}
@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/sushkevichd/day-guide-telegram-bot/pkg/domain"
+	"github.com/sushkevichd/day-guide-telegram-bot/pkg/logger"
+)
+
+var ErrHolidayCategoryNotFound = errors.New("holiday category not found")
+
+// The default name PostgreSQL gives to the category foreign key of chat_hidden_holiday_categories
+const hiddenHolidayCategoryFKey = "chat_hidden_holiday_categories_category_id_fkey"
+
+type holidayCategoryRepository struct {
+	db *sql.DB
+}
+
+func NewHolidayCategoryRepository(db *sql.DB) *holidayCategoryRepository {
+	return &holidayCategoryRepository{db: db}
+}
+
+func (repo *holidayCategoryRepository) FetchAll(ctx context.Context) ([]domain.HolidayCategory, error) {
+	q := `select id, name, emoji from holiday_categories order by id`
+
+	rows, err := repo.db.QueryContext(ctx, q)
+	if err != nil {
+		return nil, fmt.Errorf("querying holiday categories: %v", err)
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			slog.Warn("Failed to close rows", logger.Err(err))
+		}
+	}()
+
+	var categories []domain.HolidayCategory
+	for rows.Next() {
+		var c domain.HolidayCategory
+		if err := rows.Scan(&c.ID, &c.Name, &c.Emoji); err != nil {
+			return nil, fmt.Errorf("scanning rows: %v", err)
+		}
+		categories = append(categories, c)
+	}
+
+	return categories, rows.Err()
+}
+
+func (repo *holidayCategoryRepository) FetchHiddenCategoryIDs(ctx context.Context, chatID int64) ([]int64, error) {
+	q := `select category_id from chat_hidden_holiday_categories where chat_id = $1`
+
+	rows, err := repo.db.QueryContext(ctx, q, chatID)
+	if err != nil {
+		return nil, fmt.Errorf("querying hidden holiday categories: %v", err)
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			slog.Warn("Failed to close rows", logger.Err(err))
+		}
+	}()
+
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("scanning rows: %v", err)
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
+}
+
+// SetHidden hides the holidays of the category from the chat or shows them again
+func (repo *holidayCategoryRepository) SetHidden(ctx context.Context, chatID, categoryID int64, hidden bool) error {
+	q := `delete from chat_hidden_holiday_categories where chat_id = $1 and category_id = $2`
+	if hidden {
+		q = `insert into chat_hidden_holiday_categories (chat_id, category_id) values ($1, $2) on conflict do nothing`
+	}
+
+	if _, err := repo.db.ExecContext(ctx, q, chatID, categoryID); err != nil {
+		if pgErrorCode(err) == pgForeignKeyViolation {
+			if pgConstraintName(err) == hiddenHolidayCategoryFKey {
+				return ErrHolidayCategoryNotFound
+			}
+			return ErrChatNotRegistered
+		}
+		return fmt.Errorf("setting hidden holiday category: %v", err)
+	}
+
+	return nil
+}
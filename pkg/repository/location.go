@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/sushkevichd/day-guide-telegram-bot/pkg/domain"
+	"github.com/sushkevichd/day-guide-telegram-bot/pkg/logger"
+)
+
+var (
+	ErrLocationAlreadyExists = errors.New("location already added")
+	ErrLocationNotFound      = errors.New("location not found")
+)
+
+type locationRepository struct {
+	db *sql.DB
+}
+
+func NewLocationRepository(db *sql.DB) *locationRepository {
+	return &locationRepository{db: db}
+}
+
+func (repo *locationRepository) Add(ctx context.Context, chatID int64, l domain.Location) error {
+	q := `insert into chat_locations (chat_id, name, lat, lon) values ($1, $2, $3, $4)`
+
+	if _, err := repo.db.ExecContext(ctx, q, chatID, l.Name, l.Lat, l.Lon); err != nil {
+		switch pgErrorCode(err) {
+		case pgUniqueViolation:
+			return ErrLocationAlreadyExists
+		case pgForeignKeyViolation:
+			return ErrChatNotRegistered
+		}
+		return fmt.Errorf("adding location: %v", err)
+	}
+
+	return nil
+}
+
+func (repo *locationRepository) Remove(ctx context.Context, chatID int64, name string) error {
+	q := `delete from chat_locations where chat_id = $1 and lower(name) = lower($2)`
+
+	res, err := repo.db.ExecContext(ctx, q, chatID, name)
+	if err != nil {
+		return fmt.Errorf("removing location: %v", err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("getting affected rows: %v", err)
+	}
+	if affected == 0 {
+		return ErrLocationNotFound
+	}
+
+	return nil
+}
+
+func (repo *locationRepository) FetchByChatID(ctx context.Context, chatID int64) ([]domain.Location, error) {
+	q := `
+		select id, name, lat, lon
+		from chat_locations
+		where chat_id = $1
+		order by created_at, name
+	`
+
+	return repo.fetch(ctx, q, chatID)
+}
+
+// FetchAll returns the union of the locations configured by all chats, one per coordinates. Different places may
+// share a name, the weather is loaded and stored by the coordinates.
+func (repo *locationRepository) FetchAll(ctx context.Context) ([]domain.Location, error) {
+	q := `
+		select distinct on (lat, lon) id, name, lat, lon
+		from chat_locations
+		order by lat, lon, created_at
+	`
+
+	return repo.fetch(ctx, q)
+}
+
+func (repo *locationRepository) fetch(ctx context.Context, q string, args ...any) ([]domain.Location, error) {
+	rows, err := repo.db.QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, fmt.Errorf("querying locations: %v", err)
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			slog.Warn("Failed to close rows", logger.Err(err))
+		}
+	}()
+
+	var locations []domain.Location
+	for rows.Next() {
+		var l domain.Location
+		if err := rows.Scan(&l.ID, &l.Name, &l.Lat, &l.Lon); err != nil {
+			return nil, fmt.Errorf("scanning rows: %v", err)
+		}
+		locations = append(locations, l)
+	}
+
+	return locations, rows.Err()
+}
@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+var ErrMoonAdviceNotFound = errors.New("moon advice not found")
+
+type moonAdviceRepository struct {
+	db *sql.DB
+}
+
+func NewMoonAdviceRepository(db *sql.DB) *moonAdviceRepository {
+	return &moonAdviceRepository{db: db}
+}
+
+// SaveAdvice stores the advice generated for the lunar day, the date keeps the same lunar day of the next cycles apart
+func (repo *moonAdviceRepository) SaveAdvice(ctx context.Context, date time.Time, age int, advice string) error {
+	q := `
+		insert into moon_advices (date, age, advice) values ($1, $2, $3)
+		on conflict (date, age) do update set advice = excluded.advice
+	`
+
+	if _, err := repo.db.ExecContext(ctx, q, date.Format(time.DateOnly), age, advice); err != nil {
+		return fmt.Errorf("saving moon advice: %v", err)
+	}
+
+	return nil
+}
+
+func (repo *moonAdviceRepository) FetchAdvice(ctx context.Context, date time.Time, age int) (string, error) {
+	q := `select advice from moon_advices where date = $1 and age = $2`
+
+	var advice string
+	if err := repo.db.QueryRowContext(ctx, q, date.Format(time.DateOnly), age).Scan(&advice); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", ErrMoonAdviceNotFound
+		}
+		return "", fmt.Errorf("scanning row: %v", err)
+	}
+
+	return advice, nil
+}
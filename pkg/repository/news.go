@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+
+	"github.com/lib/pq"
+
+	"github.com/sushkevichd/day-guide-telegram-bot/pkg/domain"
+	"github.com/sushkevichd/day-guide-telegram-bot/pkg/logger"
+)
+
+type newsRepository struct {
+	db *sql.DB
+}
+
+func NewNewsRepository(db *sql.DB) *newsRepository {
+	return &newsRepository{db: db}
+}
+
+// FetchSentScores returns the scores the stories had when they were last sent to the chat, keyed by the ones sent
+func (repo *newsRepository) FetchSentScores(ctx context.Context, chatID int64, itemIDs []string) (map[string]int, error) {
+	q := `select item_id, score from news_sent_items where chat_id = $1 and item_id = any($2::text[])`
+
+	rows, err := repo.db.QueryContext(ctx, q, chatID, pq.Array(itemIDs))
+	if err != nil {
+		return nil, fmt.Errorf("querying sent news: %v", err)
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			slog.Warn("Failed to close rows", logger.Err(err))
+		}
+	}()
+
+	scores := map[string]int{}
+	for rows.Next() {
+		var id string
+		var score int
+		if err := rows.Scan(&id, &score); err != nil {
+			return nil, fmt.Errorf("scanning rows: %v", err)
+		}
+		scores[id] = score
+	}
+
+	return scores, rows.Err()
+}
+
+// SaveSent records the stories sent to the chat with their current score
+func (repo *newsRepository) SaveSent(ctx context.Context, chatID int64, items []domain.NewsItem) error {
+	q := `
+		insert into news_sent_items (chat_id, item_id, score) values ($1, $2, $3)
+		on conflict (chat_id, item_id) do update set score = excluded.score, sent_at = current_timestamp
+	`
+
+	tx, err := repo.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("beginning transaction: %v", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	for _, item := range items {
+		if _, err := tx.ExecContext(ctx, q, chatID, item.ID, item.Score); err != nil {
+			if pgErrorCode(err) == pgForeignKeyViolation {
+				return ErrChatNotRegistered
+			}
+			return fmt.Errorf("saving sent news item %s: %v", item.ID, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("committing transaction: %v", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/lib/pq"
+
+	"github.com/sushkevichd/day-guide-telegram-bot/pkg/domain"
+	"github.com/sushkevichd/day-guide-telegram-bot/pkg/logger"
+)
+
+var ErrNewsWatchNotFound = errors.New("news watch not found")
+
+type newsWatchRepository struct {
+	db *sql.DB
+}
+
+func NewNewsWatchRepository(db *sql.DB) *newsWatchRepository {
+	return &newsWatchRepository{db: db}
+}
+
+func (repo *newsWatchRepository) Add(ctx context.Context, w domain.NewsWatch) (int64, error) {
+	q := `insert into news_watches (chat_id, keywords, threshold) values ($1, $2::text[], $3) returning id`
+
+	var id int64
+	if err := repo.db.QueryRowContext(ctx, q, w.ChatID, pq.Array(w.Keywords), w.Threshold).Scan(&id); err != nil {
+		if pgErrorCode(err) == pgForeignKeyViolation {
+			return 0, ErrChatNotRegistered
+		}
+		return 0, fmt.Errorf("adding news watch: %v", err)
+	}
+
+	return id, nil
+}
+
+func (repo *newsWatchRepository) Remove(ctx context.Context, chatID, id int64) error {
+	q := `delete from news_watches where chat_id = $1 and id = $2`
+
+	res, err := repo.db.ExecContext(ctx, q, chatID, id)
+	if err != nil {
+		return fmt.Errorf("removing news watch: %v", err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("getting affected rows: %v", err)
+	}
+	if affected == 0 {
+		return ErrNewsWatchNotFound
+	}
+
+	return nil
+}
+
+func (repo *newsWatchRepository) FetchByChatID(ctx context.Context, chatID int64) ([]domain.NewsWatch, error) {
+	q := `select id, chat_id, keywords, threshold from news_watches where chat_id = $1 order by id`
+
+	return repo.fetch(ctx, q, chatID)
+}
+
+func (repo *newsWatchRepository) FetchAll(ctx context.Context) ([]domain.NewsWatch, error) {
+	q := `select id, chat_id, keywords, threshold from news_watches order by id`
+
+	return repo.fetch(ctx, q)
+}
+
+func (repo *newsWatchRepository) fetch(ctx context.Context, q string, args ...any) ([]domain.NewsWatch, error) {
+	rows, err := repo.db.QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, fmt.Errorf("querying news watches: %v", err)
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			slog.Warn("Failed to close rows", logger.Err(err))
+		}
+	}()
+
+	var watches []domain.NewsWatch
+	for rows.Next() {
+		var w domain.NewsWatch
+		if err := rows.Scan(&w.ID, &w.ChatID, pq.Array(&w.Keywords), &w.Threshold); err != nil {
+			return nil, fmt.Errorf("scanning rows: %v", err)
+		}
+		watches = append(watches, w)
+	}
+
+	return watches, rows.Err()
+}
+
+// MarkSent records the story as reported to the chat, false when it already was
+func (repo *newsWatchRepository) MarkSent(ctx context.Context, chatID int64, itemID string) (bool, error) {
+	q := `insert into news_watch_sent_items (chat_id, item_id) values ($1, $2) on conflict do nothing`
+
+	res, err := repo.db.ExecContext(ctx, q, chatID, itemID)
+	if err != nil {
+		return false, fmt.Errorf("marking news item as sent: %v", err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("getting affected rows: %v", err)
+	}
+
+	return affected > 0, nil
+}
@@ -0,0 +1,158 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/lib/pq"
+
+	"github.com/sushkevichd/day-guide-telegram-bot/pkg/domain"
+	"github.com/sushkevichd/day-guide-telegram-bot/pkg/logger"
+)
+
+var (
+	ErrChatNotRegistered    = errors.New("chat with the given ID is not registered")
+	ErrSubscriptionNotFound = errors.New("subscription not found")
+)
+
+type subscriptionRepository struct {
+	db *sql.DB
+}
+
+func NewSubscriptionRepository(db *sql.DB) *subscriptionRepository {
+	return &subscriptionRepository{db: db}
+}
+
+func (repo *subscriptionRepository) Subscribe(ctx context.Context, chatID int64, topic domain.Topic) error {
+	q := `
+		insert into chat_subscriptions (chat_id, topic)
+		values ($1, $2)
+		on conflict (chat_id, topic) do nothing
+	`
+
+	if _, err := repo.db.ExecContext(ctx, q, chatID, topic); err != nil {
+		if pgErrorCode(err) == pgForeignKeyViolation {
+			return ErrChatNotRegistered
+		}
+		return fmt.Errorf("subscribing chat: %v", err)
+	}
+
+	return nil
+}
+
+func (repo *subscriptionRepository) Unsubscribe(ctx context.Context, chatID int64, topic domain.Topic) error {
+	q := `delete from chat_subscriptions where chat_id = $1 and topic = $2`
+
+	if _, err := repo.db.ExecContext(ctx, q, chatID, topic); err != nil {
+		return fmt.Errorf("unsubscribing chat: %v", err)
+	}
+
+	return nil
+}
+
+func (repo *subscriptionRepository) FetchTopics(ctx context.Context, chatID int64) ([]domain.Topic, error) {
+	q := `select topic from chat_subscriptions where chat_id = $1 order by created_at`
+
+	rows, err := repo.db.QueryContext(ctx, q, chatID)
+	if err != nil {
+		return nil, fmt.Errorf("querying topics: %v", err)
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			slog.Warn("Failed to close rows", logger.Err(err))
+		}
+	}()
+
+	var topics []domain.Topic
+	for rows.Next() {
+		var topic domain.Topic
+		if err := rows.Scan(&topic); err != nil {
+			return nil, fmt.Errorf("scanning rows: %v", err)
+		}
+		topics = append(topics, topic)
+	}
+
+	return topics, rows.Err()
+}
+
+func (repo *subscriptionRepository) SetDeliveryTimes(ctx context.Context, chatID int64, topic domain.Topic, times []domain.DeliveryTime) error {
+	q := `update chat_subscriptions set delivery_times = $3 where chat_id = $1 and topic = $2`
+
+	values := make([]string, 0, len(times))
+	for _, t := range times {
+		values = append(values, t.String())
+	}
+
+	res, err := repo.db.ExecContext(ctx, q, chatID, topic, pq.Array(values))
+	if err != nil {
+		return fmt.Errorf("updating delivery times: %v", err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("getting affected rows: %v", err)
+	}
+	if affected == 0 {
+		return ErrSubscriptionNotFound
+	}
+
+	return nil
+}
+
+func (repo *subscriptionRepository) FetchSubscriptions(ctx context.Context, topic domain.Topic) ([]domain.Subscription, error) {
+	q := `
+		select s.chat_id,
+		       c.timezone,
+		       s.delivery_times
+		from chat_subscriptions s
+		join chats c on c.id = s.chat_id
+		where s.topic = $1
+	`
+
+	rows, err := repo.db.QueryContext(ctx, q, topic)
+	if err != nil {
+		return nil, fmt.Errorf("querying subscriptions: %v", err)
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			slog.Warn("Failed to close rows", logger.Err(err))
+		}
+	}()
+
+	var subscriptions []domain.Subscription
+	for rows.Next() {
+		s := domain.Subscription{Topic: topic}
+		var timezone string
+		var times []string
+
+		if err := rows.Scan(
+			&s.ChatID,
+			&timezone,
+			pq.Array(&times),
+		); err != nil {
+			return nil, fmt.Errorf("scanning rows: %v", err)
+		}
+
+		if s.Location, err = time.LoadLocation(timezone); err != nil {
+			slog.Warn("invalid chat timezone, falling back to default", "chatID", s.ChatID, "timezone", timezone, logger.Err(err))
+			s.Location, _ = time.LoadLocation(domain.DefaultTimezone)
+		}
+
+		for _, v := range times {
+			t, err := domain.ParseDeliveryTime(v)
+			if err != nil {
+				slog.Warn("invalid delivery time", "chatID", s.ChatID, "topic", topic, logger.Err(err))
+				continue
+			}
+			s.DeliveryTimes = append(s.DeliveryTimes, t)
+		}
+
+		subscriptions = append(subscriptions, s)
+	}
+
+	return subscriptions, rows.Err()
+}
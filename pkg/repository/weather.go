@@ -3,12 +3,34 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"log/slog"
 	"strings"
+	"time"
 
 	"github.com/sushkevichd/day-guide-telegram-bot/pkg/domain"
+	"github.com/sushkevichd/day-guide-telegram-bot/pkg/logger"
 )
 
+var ErrWeatherNotFound = errors.New("weather not found")
+
+const weatherColumns = `
+	created_at,
+	location,
+	temp,
+	temp_feel,
+	pressure,
+	humidity,
+	weather,
+	weather_verbose,
+	wind_speed,
+	wind_direction,
+	sunrise,
+	sunset,
+	timezone_offset
+`
+
 type weatherRepository struct {
 	db *sql.DB
 }
@@ -18,8 +40,8 @@ func NewWeatherRepository(db *sql.DB) *weatherRepository {
 }
 
 func (repo *weatherRepository) Save(ctx context.Context, w *domain.Weather) error {
-	columns := []string{"location", "temp", "temp_feel", "pressure", "humidity", "weather", "weather_verbose", "wind_speed", "wind_direction"}
-	args := []any{w.Location, w.Temp, w.TempFeel, w.Pressure, w.Humidity, w.Weather, w.WeatherVerbose, w.WindSpeed, w.WindDirection}
+	columns := []string{"location", "lat", "lon", "temp", "temp_feel", "pressure", "humidity", "weather", "weather_verbose", "wind_speed", "wind_direction", "sunrise", "sunset", "timezone_offset"}
+	args := []any{w.Location, w.Lat, w.Lon, w.Temp, w.TempFeel, w.Pressure, w.Humidity, w.Weather, w.WeatherVerbose, w.WindSpeed, w.WindDirection, nullTime(w.Sunrise), nullTime(w.Sunset), w.TimezoneOffset}
 
 	placeholders := make([]string, len(columns))
 	for i := range columns {
@@ -37,24 +59,77 @@ func (repo *weatherRepository) Save(ctx context.Context, w *domain.Weather) erro
 
 func (repo *weatherRepository) FetchLatestByLocation(ctx context.Context, location domain.Location) (*domain.Weather, error) {
 	q := `
-		select 
-		    location,
-		    temp,
-		    temp_feel,
-		    pressure,
-			humidity,
-			weather,
-			weather_verbose,
-			wind_speed,
-			wind_direction
+		select ` + weatherColumns + `
 		from weather
-		where location = $1
+		where lat = $1 and lon = $2
 		order by created_at desc
 		limit 1;
 	`
 
+	return scanWeather(repo.db.QueryRowContext(ctx, q, location.Lat, location.Lon))
+}
+
+// FetchYesterdayByLocation returns the weather saved closest to 24 hours ago, within an hour of it
+func (repo *weatherRepository) FetchYesterdayByLocation(ctx context.Context, location domain.Location) (*domain.Weather, error) {
+	return repo.FetchClosestByLocation(ctx, location, 24*time.Hour, time.Hour)
+}
+
+// FetchClosestByLocation returns the weather saved closest to the given time ago, within the window around it
+func (repo *weatherRepository) FetchClosestByLocation(ctx context.Context, location domain.Location, ago, window time.Duration) (*domain.Weather, error) {
+	q := `
+		select ` + weatherColumns + `
+		from weather
+		where lat = $1 and lon = $2
+		  and created_at between localtimestamp - ($3::float8 + $4::float8) * interval '1 second'
+		                     and localtimestamp - ($3::float8 - $4::float8) * interval '1 second'
+		order by abs(extract(epoch from created_at - (localtimestamp - $3::float8 * interval '1 second')))
+		limit 1;
+	`
+
+	return scanWeather(repo.db.QueryRowContext(ctx, q, location.Lat, location.Lon, int64(ago.Seconds()), int64(window.Seconds())))
+}
+
+// FetchHistoryByLocation returns the weather saved for the location during the given number of days, oldest first
+func (repo *weatherRepository) FetchHistoryByLocation(ctx context.Context, location domain.Location, days int) ([]domain.Weather, error) {
+	q := `
+		select ` + weatherColumns + `
+		from weather
+		where lat = $1 and lon = $2
+		  and created_at >= localtimestamp - $3::float8 * interval '1 day'
+		order by created_at
+	`
+
+	rows, err := repo.db.QueryContext(ctx, q, location.Lat, location.Lon, days)
+	if err != nil {
+		return nil, fmt.Errorf("querying weather history: %v", err)
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			slog.Warn("Failed to close rows", logger.Err(err))
+		}
+	}()
+
+	var history []domain.Weather
+	for rows.Next() {
+		w, err := scanWeather(rows)
+		if err != nil {
+			return nil, err
+		}
+		history = append(history, *w)
+	}
+
+	return history, rows.Err()
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanWeather(row rowScanner) (*domain.Weather, error) {
 	var w domain.Weather
-	if err := repo.db.QueryRowContext(ctx, q, location).Scan(
+	var sunrise, sunset sql.NullTime
+	if err := row.Scan(
+		&w.Timestamp,
 		&w.Location,
 		&w.Temp,
 		&w.TempFeel,
@@ -64,9 +139,21 @@ func (repo *weatherRepository) FetchLatestByLocation(ctx context.Context, locati
 		&w.WeatherVerbose,
 		&w.WindSpeed,
 		&w.WindDirection,
+		&sunrise,
+		&sunset,
+		&w.TimezoneOffset,
 	); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrWeatherNotFound
+		}
 		return nil, fmt.Errorf("scanning row: %v", err)
 	}
+	w.Sunrise = sunrise.Time
+	w.Sunset = sunset.Time
 
 	return &w, nil
 }
+
+func nullTime(t time.Time) sql.NullTime {
+	return sql.NullTime{Time: t, Valid: !t.IsZero()}
+}
@@ -0,0 +1,130 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/sushkevichd/day-guide-telegram-bot/pkg/domain"
+	"github.com/sushkevichd/day-guide-telegram-bot/pkg/logger"
+)
+
+var ErrWeatherAlertNotFound = errors.New("weather alert not found")
+
+type weatherAlertRepository struct {
+	db *sql.DB
+}
+
+func NewWeatherAlertRepository(db *sql.DB) *weatherAlertRepository {
+	return &weatherAlertRepository{db: db}
+}
+
+// SetRule adds the rule or changes the threshold of an existing one
+func (repo *weatherAlertRepository) SetRule(ctx context.Context, r domain.WeatherAlertRule) error {
+	q := `
+		insert into weather_alert_rules (chat_id, kind, threshold) values ($1, $2, $3)
+		on conflict (chat_id, kind) do update set threshold = excluded.threshold
+	`
+
+	if _, err := repo.db.ExecContext(ctx, q, r.ChatID, r.Kind, r.Threshold); err != nil {
+		if pgErrorCode(err) == pgForeignKeyViolation {
+			return ErrChatNotRegistered
+		}
+		return fmt.Errorf("setting weather alert rule: %v", err)
+	}
+
+	return nil
+}
+
+func (repo *weatherAlertRepository) RemoveRule(ctx context.Context, chatID int64, kind domain.WeatherAlertKind) error {
+	q := `delete from weather_alert_rules where chat_id = $1 and kind = $2`
+
+	res, err := repo.db.ExecContext(ctx, q, chatID, kind)
+	if err != nil {
+		return fmt.Errorf("removing weather alert rule: %v", err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("getting affected rows: %v", err)
+	}
+	if affected == 0 {
+		return ErrWeatherAlertNotFound
+	}
+
+	return nil
+}
+
+func (repo *weatherAlertRepository) FetchRulesByChatID(ctx context.Context, chatID int64) ([]domain.WeatherAlertRule, error) {
+	q := `select chat_id, kind, threshold from weather_alert_rules where chat_id = $1 order by kind`
+
+	rows, err := repo.db.QueryContext(ctx, q, chatID)
+	if err != nil {
+		return nil, fmt.Errorf("querying weather alert rules: %v", err)
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			slog.Warn("Failed to close rows", logger.Err(err))
+		}
+	}()
+
+	var rules []domain.WeatherAlertRule
+	for rows.Next() {
+		var r domain.WeatherAlertRule
+		if err := rows.Scan(&r.ChatID, &r.Kind, &r.Threshold); err != nil {
+			return nil, fmt.Errorf("scanning rows: %v", err)
+		}
+		rules = append(rules, r)
+	}
+
+	return rules, rows.Err()
+}
+
+// FetchStatesByLocation returns the rules of all chats having a location at the coordinates, with their state for it.
+// The states are kept under the chat's name of the location.
+func (repo *weatherAlertRepository) FetchStatesByLocation(ctx context.Context, location domain.Location) ([]domain.WeatherAlertState, error) {
+	q := `
+		select r.chat_id, r.kind, r.threshold, l.name, coalesce(s.active, false), s.fired_at
+		from weather_alert_rules r
+		join chat_locations l on l.chat_id = r.chat_id and l.lat = $1 and l.lon = $2
+		left join weather_alert_states s on s.chat_id = r.chat_id and s.kind = r.kind and s.location = l.name
+	`
+
+	rows, err := repo.db.QueryContext(ctx, q, location.Lat, location.Lon)
+	if err != nil {
+		return nil, fmt.Errorf("querying weather alert states: %v", err)
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			slog.Warn("Failed to close rows", logger.Err(err))
+		}
+	}()
+
+	var states []domain.WeatherAlertState
+	for rows.Next() {
+		var s domain.WeatherAlertState
+		var firedAt sql.NullTime
+		if err := rows.Scan(&s.Rule.ChatID, &s.Rule.Kind, &s.Rule.Threshold, &s.Location, &s.Active, &firedAt); err != nil {
+			return nil, fmt.Errorf("scanning rows: %v", err)
+		}
+		s.FiredAt = firedAt.Time
+		states = append(states, s)
+	}
+
+	return states, rows.Err()
+}
+
+func (repo *weatherAlertRepository) SaveState(ctx context.Context, s domain.WeatherAlertState) error {
+	q := `
+		insert into weather_alert_states (chat_id, kind, location, active, fired_at) values ($1, $2, $3, $4, $5)
+		on conflict (chat_id, kind, location) do update set active = excluded.active, fired_at = excluded.fired_at
+	`
+
+	if _, err := repo.db.ExecContext(ctx, q, s.Rule.ChatID, s.Rule.Kind, s.Location, s.Active, nullTime(s.FiredAt)); err != nil {
+		return fmt.Errorf("saving weather alert state: %v", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,76 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/sushkevichd/day-guide-telegram-bot/pkg/domain"
+	"github.com/sushkevichd/day-guide-telegram-bot/pkg/logger"
+)
+
+type ExchangeRateAlertStore interface {
+	FetchAll(ctx context.Context) ([]domain.ExchangeRateAlert, error)
+	UpdateState(ctx context.Context, a domain.ExchangeRateAlert) error
+}
+
+// ExchangeRateAlertService evaluates the alerts of all chats against every saved exchange rate snapshot
+type ExchangeRateAlertService struct {
+	store ExchangeRateAlertStore
+	outCh chan<- domain.Message
+}
+
+func NewExchangeRateAlertService(store ExchangeRateAlertStore, outCh chan<- domain.Message) *ExchangeRateAlertService {
+	return &ExchangeRateAlertService{
+		store: store,
+		outCh: outCh,
+	}
+}
+
+func (s *ExchangeRateAlertService) OnSave(ctx context.Context, snapshot *domain.ExchangeRateSnapshot) error {
+	alerts, err := s.store.FetchAll(ctx)
+	if err != nil {
+		return fmt.Errorf("fetching alerts: %v", err)
+	}
+
+	for _, alert := range alerts {
+		rate, err := snapshot.Rate(alert.Pair)
+		if err != nil {
+			if !errors.Is(err, domain.ErrUnknownCurrency) {
+				slog.Error("computing alert rate", "alert", alert.ID, logger.Err(err))
+			}
+			continue
+		}
+
+		previous := alert
+		fire := alert.Evaluate(rate.Rate)
+		if alert.Triggered != previous.Triggered || alert.ReferenceRate != previous.ReferenceRate {
+			if err := s.store.UpdateState(ctx, alert); err != nil {
+				// Notifying without the stored state would repeat the alert on the next pass
+				slog.Error("updating alert state", "alert", alert.ID, logger.Err(err))
+				continue
+			}
+		}
+
+		if fire {
+			s.outCh <- &domain.TextMessage{
+				ChatID:  alert.ChatID,
+				Content: alertMessage(previous, rate.Rate),
+			}
+		}
+	}
+
+	return nil
+}
+
+func alertMessage(alert domain.ExchangeRateAlert, rate float64) string {
+	switch alert.Condition {
+	case domain.AlertAbove:
+		return fmt.Sprintf("🔔 %s: *%.4f* выше %g", alert.Pair, rate, alert.Threshold)
+	case domain.AlertBelow:
+		return fmt.Sprintf("🔔 %s: *%.4f* ниже %g", alert.Pair, rate, alert.Threshold)
+	default:
+		return fmt.Sprintf("🔔 %s: *%.4f*, изменение %+.2f%% с %.4f", alert.Pair, rate, alert.ChangePercent(rate), alert.ReferenceRate)
+	}
+}
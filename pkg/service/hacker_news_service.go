@@ -1,37 +1,75 @@
 package service
 
 import (
+	"context"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/sushkevichd/day-guide-telegram-bot/pkg/domain"
+	"github.com/sushkevichd/day-guide-telegram-bot/pkg/hackernews"
+	"github.com/sushkevichd/day-guide-telegram-bot/pkg/logger"
 	"github.com/sushkevichd/day-guide-telegram-bot/pkg/parser"
 )
 
+const (
+	// hackerNewsPageSize is the number of stories on a page of the site, the API lists are fetched to the same depth
+	hackerNewsPageSize = 30
+	hackerNewsCacheTTL = 5 * time.Minute
+	hackerNewsTimeout  = 10 * time.Second
+)
+
+// Pages of the site scraped when the API fails
+var hackerNewsPages = map[domain.NewsList]string{
+	domain.NewsTop:  "https://news.ycombinator.com/news",
+	domain.NewsBest: "https://news.ycombinator.com/best",
+	domain.NewsNew:  "https://news.ycombinator.com/newest",
+	domain.NewsAsk:  "https://news.ycombinator.com/ask",
+	domain.NewsShow: "https://news.ycombinator.com/show",
+}
+
 type HackerNewsParser interface {
 	Parse(html string) ([]domain.NewsItem, error)
 }
 
+type HackerNewsAPI interface {
+	FetchStories(ctx context.Context, list domain.NewsList, limit int) ([]domain.NewsItem, error)
+}
+
+// cachedNews is the cache of a list, its lock is held while fetching it, so that concurrent callers wait for one fetch
+// instead of starting their own, while the other lists are served
+type cachedNews struct {
+	mu        sync.Mutex
+	items     []domain.NewsItem
+	fetchedAt time.Time
+}
+
 type HackerNewsService struct {
-	URL    string
+	API    HackerNewsAPI
 	Parser HackerNewsParser
+	Client *http.Client
+
+	mu    sync.Mutex
+	cache map[domain.NewsList]*cachedNews
 }
 
 func NewsHackerNewsService() *HackerNewsService {
 	return &HackerNewsService{
-		URL:    "https://news.ycombinator.com/news",
+		API:    hackernews.NewClient(),
 		Parser: parser.HackerNewsParser{},
+		Client: &http.Client{Timeout: hackerNewsTimeout},
+		cache:  map[domain.NewsList]*cachedNews{},
 	}
 }
 
-func (s HackerNewsService) GetNews(limit int) ([]domain.NewsItem, error) {
-	html, err := s.fetchHTML(s.URL)
-	if err != nil {
-		return nil, err
-	}
-	items, err := s.Parser.Parse(html)
+// GetNews returns up to limit stories of the list, they are fetched from the API, or scraped when it fails, at most
+// once per cache TTL
+func (s *HackerNewsService) GetNews(ctx context.Context, list domain.NewsList, limit int) ([]domain.NewsItem, error) {
+	items, err := s.cachedNews(ctx, list)
 	if err != nil {
 		return nil, err
 	}
@@ -41,8 +79,13 @@ func (s HackerNewsService) GetNews(limit int) ([]domain.NewsItem, error) {
 	return items, nil
 }
 
-func (s HackerNewsService) GetNewsAsText(limit int) (string, error) {
-	items, err := s.GetNews(limit)
+// FetchData returns the front page stories, it makes the service a loader fetcher
+func (s *HackerNewsService) FetchData(ctx context.Context) ([]domain.NewsItem, error) {
+	return s.GetNews(ctx, domain.NewsTop, 0)
+}
+
+func (s *HackerNewsService) GetNewsAsText(ctx context.Context, list domain.NewsList, limit int) (string, error) {
+	items, err := s.GetNews(ctx, list, limit)
 	if err != nil {
 		return "", err
 	}
@@ -54,8 +97,56 @@ func (s HackerNewsService) GetNewsAsText(limit int) (string, error) {
 	return sb.String(), nil
 }
 
-func (s *HackerNewsService) fetchHTML(url string) (string, error) {
-	resp, err := http.Get(url)
+func (s *HackerNewsService) cachedNews(ctx context.Context, list domain.NewsList) ([]domain.NewsItem, error) {
+	s.mu.Lock()
+	cached, ok := s.cache[list]
+	if !ok {
+		cached = &cachedNews{}
+		s.cache[list] = cached
+	}
+	s.mu.Unlock()
+
+	cached.mu.Lock()
+	defer cached.mu.Unlock()
+
+	if cached.items != nil && time.Since(cached.fetchedAt) < hackerNewsCacheTTL {
+		return cached.items, nil
+	}
+
+	items, err := s.API.FetchStories(ctx, list, hackerNewsPageSize)
+	if err != nil {
+		slog.Warn("hacker news api failed, scraping the site", "list", list, logger.Err(err))
+
+		items, err = s.scrape(ctx, list)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	cached.items, cached.fetchedAt = items, time.Now()
+	return items, nil
+}
+
+func (s *HackerNewsService) scrape(ctx context.Context, list domain.NewsList) ([]domain.NewsItem, error) {
+	url, ok := hackerNewsPages[list]
+	if !ok {
+		return nil, fmt.Errorf("unknown news list %q", list)
+	}
+
+	html, err := s.fetchHTML(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	return s.Parser.Parse(html)
+}
+
+func (s *HackerNewsService) fetchHTML(ctx context.Context, url string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create request for URL %s: %w", url, err)
+	}
+
+	resp, err := s.Client.Do(req)
 	if err != nil {
 		return "", fmt.Errorf("failed to fetch URL %s: %w", url, err)
 	}
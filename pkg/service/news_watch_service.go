@@ -0,0 +1,72 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/sushkevichd/day-guide-telegram-bot/pkg/domain"
+	"github.com/sushkevichd/day-guide-telegram-bot/pkg/formatter"
+	"github.com/sushkevichd/day-guide-telegram-bot/pkg/logger"
+)
+
+type NewsWatchStore interface {
+	FetchAll(ctx context.Context) ([]domain.NewsWatch, error)
+	MarkSent(ctx context.Context, chatID int64, itemID string) (bool, error)
+}
+
+// NewsWatchService reports the polled front page stories matching the watches of the chats, it is the saver of the
+// news watch loader
+type NewsWatchService struct {
+	store NewsWatchStore
+	outCh chan<- domain.Message
+}
+
+func NewNewsWatchService(store NewsWatchStore, outCh chan<- domain.Message) *NewsWatchService {
+	return &NewsWatchService{
+		store: store,
+		outCh: outCh,
+	}
+}
+
+func (s *NewsWatchService) Save(ctx context.Context, items []domain.NewsItem) error {
+	watches, err := s.store.FetchAll(ctx)
+	if err != nil {
+		return fmt.Errorf("fetching news watches: %v", err)
+	}
+
+	for _, watch := range watches {
+		for _, item := range items {
+			if !watch.Matches(item) {
+				continue
+			}
+
+			// Marking before sending may lose a story when sending fails, but never repeats one
+			first, err := s.store.MarkSent(ctx, watch.ChatID, item.ID)
+			if err != nil {
+				slog.Error("marking news item as sent", "chatID", watch.ChatID, "item", item.ID, logger.Err(err))
+				continue
+			}
+			if !first {
+				continue
+			}
+
+			s.outCh <- &domain.TextMessage{
+				ChatID:  watch.ChatID,
+				Content: newsWatchMessage(watch, item),
+			}
+		}
+	}
+
+	return nil
+}
+
+func newsWatchMessage(watch domain.NewsWatch, item domain.NewsItem) string {
+	title := formatter.StripMarkdown(item.Title)
+
+	return fmt.Sprintf(
+		"👀 Hacker News: [%s](%s)\n⭐ %d, 💬 %d\nКлючевые слова: %s",
+		title, item.URL, item.Score, item.Comments, strings.Join(watch.MatchedKeywords(item), ", "),
+	)
+}
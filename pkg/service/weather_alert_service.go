@@ -0,0 +1,118 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"time"
+
+	"github.com/sushkevichd/day-guide-telegram-bot/pkg/domain"
+	"github.com/sushkevichd/day-guide-telegram-bot/pkg/formatter"
+	"github.com/sushkevichd/day-guide-telegram-bot/pkg/logger"
+	"github.com/sushkevichd/day-guide-telegram-bot/pkg/repository"
+)
+
+// weatherAlertCooldown keeps a condition flapping between polls, e.g. a storm with breaks, from firing again
+const weatherAlertCooldown = 6 * time.Hour
+
+type WeatherAlertStore interface {
+	FetchStatesByLocation(ctx context.Context, location domain.Location) ([]domain.WeatherAlertState, error)
+	SaveState(ctx context.Context, s domain.WeatherAlertState) error
+}
+
+type WeatherHistoryFetcher interface {
+	FetchYesterdayByLocation(ctx context.Context, location domain.Location) (*domain.Weather, error)
+}
+
+// WeatherAlertService evaluates the weather alert rules of the chats having the location of every saved weather
+type WeatherAlertService struct {
+	store   WeatherAlertStore
+	history WeatherHistoryFetcher
+	outCh   chan<- domain.Message
+}
+
+func NewWeatherAlertService(
+	store WeatherAlertStore,
+	history WeatherHistoryFetcher,
+	outCh chan<- domain.Message,
+) *WeatherAlertService {
+	return &WeatherAlertService{
+		store:   store,
+		history: history,
+		outCh:   outCh,
+	}
+}
+
+func (s *WeatherAlertService) OnSave(ctx context.Context, w *domain.Weather) error {
+	location := domain.Location{Name: w.Location, Lat: w.Lat, Lon: w.Lon}
+
+	states, err := s.store.FetchStatesByLocation(ctx, location)
+	if err != nil {
+		return fmt.Errorf("fetching weather alert states: %v", err)
+	}
+	if len(states) == 0 {
+		return nil
+	}
+
+	// Without yesterday's weather the rules comparing with it do not hold
+	yesterday, err := s.history.FetchYesterdayByLocation(ctx, location)
+	if err != nil && !errors.Is(err, repository.ErrWeatherNotFound) {
+		return fmt.Errorf("fetching yesterday weather: %v", err)
+	}
+
+	now := time.Now()
+	for _, state := range states {
+		message, holds := checkWeatherAlert(state.Rule, *w, yesterday)
+
+		fire := holds && !state.Active && now.Sub(state.FiredAt) > weatherAlertCooldown
+		if holds == state.Active && !fire {
+			continue
+		}
+
+		state.Active = holds
+		if fire {
+			state.FiredAt = now
+		}
+		if err := s.store.SaveState(ctx, state); err != nil {
+			// Notifying without the stored state would repeat the alert on the next pass
+			slog.Error("saving weather alert state", "chatID", state.Rule.ChatID, "kind", state.Rule.Kind, logger.Err(err))
+			continue
+		}
+
+		if fire {
+			s.outCh <- &domain.TextMessage{
+				ChatID:  state.Rule.ChatID,
+				Content: fmt.Sprintf("⚠️ %s: %s", state.Location, message),
+			}
+		}
+	}
+
+	return nil
+}
+
+// checkWeatherAlert reports whether the rule condition holds and describes it
+func checkWeatherAlert(rule domain.WeatherAlertRule, current domain.Weather, yesterday *domain.Weather) (string, bool) {
+	switch rule.Kind {
+	case domain.WeatherAlertTempDrop:
+		if yesterday == nil {
+			return "", false
+		}
+		drop := yesterday.Temp - current.Temp
+		return fmt.Sprintf("температура упала на *%.1f°C* за сутки, сейчас *%.1f°C*", drop, current.Temp), drop > rule.Threshold
+	case domain.WeatherAlertWind:
+		return fmt.Sprintf("ветер *%.1fм/c*", current.WindSpeed), current.WindSpeed > rule.Threshold
+	case domain.WeatherAlertStorm:
+		return current.WeatherVerbose, current.Weather == "Thunderstorm" || current.Weather == "Snow"
+	case domain.WeatherAlertPressure:
+		if yesterday == nil {
+			return "", false
+		}
+		change := formatter.HPaToMmHg(current.Pressure) - formatter.HPaToMmHg(yesterday.Pressure)
+		return fmt.Sprintf("давление изменилось на *%+dмм* за сутки, сейчас *%dмм*", change, formatter.HPaToMmHg(current.Pressure)),
+			math.Abs(float64(change)) > rule.Threshold
+	default:
+		return "", false
+	}
+}
@@ -2,8 +2,10 @@ package telegram
 
 import (
 	"context"
+	"crypto/subtle"
 	"fmt"
 	"log/slog"
+	"net/http"
 	"strings"
 	"time"
 	"unicode/utf8"
@@ -15,15 +17,28 @@ import (
 	"github.com/sushkevichd/day-guide-telegram-bot/pkg/domain"
 )
 
-const maxTelegramMessageLength = 4096
+const (
+	maxTelegramMessageLength = 4096
+	secretTokenHeader        = "X-Telegram-Bot-Api-Secret-Token"
+)
 
 type client struct {
-	token   string
-	bot     *tgbotapi.BotAPI
-	updates tgbotapi.UpdatesChannel
+	token         string
+	bot           *tgbotapi.BotAPI
+	updates       tgbotapi.UpdatesChannel
+	webhookUpdate chan tgbotapi.Update
+	webhookSecret string
+}
+
+// WebhookConfig enables receiving updates pushed by Telegram instead of long polling
+type WebhookConfig struct {
+	URL         string
+	SecretToken string
 }
 
-func NewClient(token string) (*client, error) {
+// NewClient creates a client receiving updates with long polling or, when webhook is set,
+// with a webhook which updates must be served with WebhookHandler.
+func NewClient(token string, webhook *WebhookConfig) (*client, error) {
 	bot, err := tgbotapi.NewBotAPI(token)
 	if err != nil {
 		return nil, fmt.Errorf("creating bot api: %v", err)
@@ -31,6 +46,15 @@ func NewClient(token string) (*client, error) {
 
 	slog.Info("authorized on telegram", "bot", bot.Self)
 
+	if webhook != nil {
+		return newWebhookClient(token, bot, webhook)
+	}
+
+	// getUpdates is rejected by Telegram while a webhook is registered
+	if _, err := bot.Request(tgbotapi.DeleteWebhookConfig{}); err != nil {
+		return nil, fmt.Errorf("deleting webhook: %v", err)
+	}
+
 	u := tgbotapi.NewUpdate(0)
 	u.Timeout = 60
 
@@ -41,12 +65,84 @@ func NewClient(token string) (*client, error) {
 	}, nil
 }
 
+func newWebhookClient(token string, bot *tgbotapi.BotAPI, webhook *WebhookConfig) (*client, error) {
+	if webhook.URL == "" || webhook.SecretToken == "" {
+		return nil, fmt.Errorf("webhook url and secret token cannot be empty")
+	}
+
+	// The library's WebhookConfig has no secret_token field yet
+	params := tgbotapi.Params{
+		"url":          webhook.URL,
+		"secret_token": webhook.SecretToken,
+	}
+	if _, err := bot.MakeRequest("setWebhook", params); err != nil {
+		return nil, fmt.Errorf("setting webhook: %v", err)
+	}
+
+	slog.Info("registered telegram webhook", "url", webhook.URL)
+
+	updates := make(chan tgbotapi.Update, bot.Buffer)
+
+	return &client{
+		token:         token,
+		bot:           bot,
+		updates:       updates,
+		webhookUpdate: updates,
+		webhookSecret: webhook.SecretToken,
+	}, nil
+}
+
+// WebhookHandler accepts the updates pushed by Telegram and feeds them into GetUpdates
+func (c *client) WebhookHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if c.webhookUpdate == nil {
+			http.Error(w, "webhook mode is disabled", http.StatusNotFound)
+			return
+		}
+
+		secret := r.Header.Get(secretTokenHeader)
+		if subtle.ConstantTimeCompare([]byte(secret), []byte(c.webhookSecret)) != 1 {
+			slog.Warn("webhook request with invalid secret token", "remote", r.RemoteAddr)
+			http.Error(w, "invalid secret token", http.StatusUnauthorized)
+			return
+		}
+
+		update, err := c.bot.HandleUpdate(r)
+		if err != nil {
+			slog.Warn("decoding webhook update", logger.Err(err))
+			http.Error(w, "invalid update", http.StatusBadRequest)
+			return
+		}
+
+		select {
+		case c.webhookUpdate <- *update:
+			w.WriteHeader(http.StatusOK)
+		case <-r.Context().Done():
+			// Telegram redelivers the update when it doesn't get a successful response
+			http.Error(w, "update queue is full", http.StatusServiceUnavailable)
+		}
+	})
+}
+
+func (c *client) BotName() string {
+	return c.bot.Self.UserName
+}
+
+// SetCommands publishes the command list shown in the Telegram menu
+func (c *client) SetCommands(commands []tgbotapi.BotCommand) error {
+	if _, err := c.bot.Request(tgbotapi.NewSetMyCommands(commands...)); err != nil {
+		return fmt.Errorf("setting bot commands: %v", err)
+	}
+	return nil
+}
+
 func (c *client) GetUpdates() tgbotapi.UpdatesChannel {
 	return c.updates
 }
 
 func (c *client) Send(message domain.Message) error {
-	if _, err := c.bot.Send(message.ToChatMessage()); err != nil {
+	// Request is used instead of Send as not every method responds with a message, e.g. answerCallbackQuery
+	if _, err := c.bot.Request(message.ToChatMessage()); err != nil {
 		return fmt.Errorf("sending message: %v", err)
 	}
 	return nil
@@ -60,6 +156,11 @@ func (c *client) SendResponse(ctx context.Context, chatID int64, response string
 	}
 }
 
+// SendMarkdown sends the Markdown text rendered to HTML, split into several messages when long
+func (c *client) SendMarkdown(ctx context.Context, chatID int64, text string) error {
+	return c.sendText(ctx, chatID, text)
+}
+
 func (c *client) handleError(ctx context.Context, chatID int64, err error) {
 	slog.ErrorContext(ctx, "Error during sending message", logger.Err(err))
 
@@ -88,7 +189,7 @@ func (c *client) sendText(ctx context.Context, chatID int64, text string) error
 		}
 
 		cutIndex := c.findCutIndex(htmlText, maxTelegramMessageLength)
-		if err := c.send(chatID, htmlText); err != nil {
+		if err := c.send(chatID, htmlText[:cutIndex]); err != nil {
 			return err
 		}
 		htmlText = htmlText[cutIndex:]
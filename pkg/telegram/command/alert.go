@@ -0,0 +1,249 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"github.com/sushkevichd/day-guide-telegram-bot/pkg/domain"
+	"github.com/sushkevichd/day-guide-telegram-bot/pkg/logger"
+	"github.com/sushkevichd/day-guide-telegram-bot/pkg/repository"
+	"github.com/sushkevichd/day-guide-telegram-bot/pkg/telegram"
+)
+
+const (
+	alertsCommand        = "alerts"
+	alertsCallbackDelete = "delete"
+
+	alertUsage = "Usage: /alert USD RUB > 100, /alert USD RUB < 90, /alert USD/RUB change 2%"
+)
+
+type AlertManager interface {
+	Add(ctx context.Context, a domain.ExchangeRateAlert) (int64, error)
+	Remove(ctx context.Context, chatID, id int64) error
+	FetchByChatID(ctx context.Context, chatID int64) ([]domain.ExchangeRateAlert, error)
+}
+
+type LatestExchangeRateFetcher interface {
+	FetchLatestRate(ctx context.Context, pair domain.CurrencyPair) (*domain.ExchangeRate, error)
+}
+
+type alert struct {
+	manager     AlertManager
+	rateFetcher LatestExchangeRateFetcher
+	outCh       chan<- domain.Message
+}
+
+func NewAlert(
+	manager AlertManager,
+	rateFetcher LatestExchangeRateFetcher,
+	outCh chan<- domain.Message,
+) *alert {
+	return &alert{
+		manager:     manager,
+		rateFetcher: rateFetcher,
+		outCh:       outCh,
+	}
+}
+
+func (a *alert) Spec() telegram.CommandSpec {
+	return telegram.CommandSpec{
+		Name:        "alert",
+		Description: "Notify the chat when an exchange rate crosses a threshold, e.g. /alert USD RUB > 100",
+	}
+}
+
+func (a *alert) Execute(update *tgbotapi.Update, args []string) {
+	ctx := context.TODO()
+	chatID := update.Message.Chat.ID
+
+	// The pair takes one argument as "USD/RUB" or two as "USD RUB", the condition always takes the last two
+	if len(args) < 3 || len(args) > 4 {
+		a.reply(update, alertUsage)
+		return
+	}
+
+	pair, err := parseCurrencyPair(args[:len(args)-2])
+	if err != nil {
+		a.reply(update, fmt.Sprintf("%v. %s", err, alertUsage))
+		return
+	}
+
+	condition, threshold, err := domain.ParseAlertCondition(args[len(args)-2:])
+	if err != nil {
+		a.reply(update, fmt.Sprintf("%v. %s", err, alertUsage))
+		return
+	}
+
+	newAlert := domain.ExchangeRateAlert{
+		ChatID:    chatID,
+		Pair:      pair,
+		Condition: condition,
+		Threshold: threshold,
+	}
+
+	// Seeding the state with the current rate makes the alert fire on the next crossing, not right away
+	current := "no rate loaded yet"
+	rate, err := a.rateFetcher.FetchLatestRate(ctx, pair)
+	switch {
+	case err == nil:
+		newAlert.Evaluate(rate.Rate)
+		current = fmt.Sprintf("current rate %.4f", rate.Rate)
+	case errors.Is(err, domain.ErrUnknownCurrency):
+		a.reply(update, err.Error())
+		return
+	default:
+		slog.Warn("fetching latest rate for alert", "pair", pair, logger.Err(err))
+	}
+
+	id, err := a.manager.Add(ctx, newAlert)
+	if err != nil {
+		slog.Error("adding alert", "chatID", chatID, "alert", newAlert.String(), logger.Err(err))
+
+		msg := "Failed to add alert"
+		if errors.Is(err, repository.ErrChatNotRegistered) {
+			msg = "Register the chat with /register first"
+		}
+		a.reply(update, msg)
+		return
+	}
+
+	a.reply(update, fmt.Sprintf("Alert #%d added: %s (%s)", id, newAlert.String(), current))
+}
+
+func (a *alert) reply(update *tgbotapi.Update, content string) {
+	a.outCh <- &domain.TextMessage{
+		ChatID:           update.Message.Chat.ID,
+		ReplyToMessageID: update.Message.MessageID,
+		Content:          content,
+	}
+}
+
+type alerts struct {
+	manager AlertManager
+	outCh   chan<- domain.Message
+}
+
+func NewAlerts(
+	manager AlertManager,
+	outCh chan<- domain.Message,
+) *alerts {
+	return &alerts{
+		manager: manager,
+		outCh:   outCh,
+	}
+}
+
+func (a *alerts) Spec() telegram.CommandSpec {
+	return telegram.CommandSpec{
+		Name:        alertsCommand,
+		Description: "List exchange rate alerts, /alerts delete <id> removes one",
+	}
+}
+
+func (a *alerts) Execute(update *tgbotapi.Update, args []string) {
+	ctx := context.TODO()
+	chatID := update.Message.Chat.ID
+
+	if len(args) == 2 && strings.EqualFold(args[0], alertsCallbackDelete) {
+		id, err := strconv.ParseInt(strings.TrimPrefix(args[1], "#"), 10, 64)
+		if err != nil {
+			a.reply(update, "Usage: /alerts delete <id>")
+			return
+		}
+
+		msg := fmt.Sprintf("Alert #%d deleted", id)
+		if err := a.manager.Remove(ctx, chatID, id); err != nil {
+			if errors.Is(err, repository.ErrAlertNotFound) {
+				msg = fmt.Sprintf("Alert #%d not found", id)
+			} else {
+				slog.Error("removing alert", "chatID", chatID, "id", id, logger.Err(err))
+				msg = "Failed to delete alert"
+			}
+		}
+		a.reply(update, msg)
+		return
+	}
+
+	content, keyboard := a.list(ctx, chatID)
+	a.outCh <- &domain.TextMessage{
+		ChatID:           chatID,
+		ReplyToMessageID: update.Message.MessageID,
+		Content:          content,
+		ReplyMarkup:      keyboard,
+	}
+}
+
+// HandleCallback deletes the alert picked with the inline keyboard and refreshes the list
+func (a *alerts) HandleCallback(query *tgbotapi.CallbackQuery, args []string) {
+	ctx := context.TODO()
+	chatID := query.Message.Chat.ID
+
+	if len(args) != 2 || args[0] != alertsCallbackDelete {
+		slog.Warn("invalid alerts callback", "data", query.Data)
+		return
+	}
+
+	id, err := strconv.ParseInt(args[1], 10, 64)
+	if err != nil {
+		slog.Warn("invalid alerts callback", "data", query.Data, logger.Err(err))
+		return
+	}
+
+	// A double tap finds the alert already deleted, the refreshed list is still what the user expects
+	if err := a.manager.Remove(ctx, chatID, id); err != nil && !errors.Is(err, repository.ErrAlertNotFound) {
+		slog.Error("removing alert", "chatID", chatID, "id", id, logger.Err(err))
+		return
+	}
+
+	content, keyboard := a.list(ctx, chatID)
+	a.outCh <- &domain.EditTextMessage{
+		ChatID:      chatID,
+		MessageID:   query.Message.MessageID,
+		Content:     content,
+		ReplyMarkup: keyboard,
+	}
+}
+
+func (a *alerts) list(ctx context.Context, chatID int64) (string, *tgbotapi.InlineKeyboardMarkup) {
+	chatAlerts, err := a.manager.FetchByChatID(ctx, chatID)
+	if err != nil {
+		slog.Error("fetching alerts", "chatID", chatID, logger.Err(err))
+		return "Failed to fetch alerts", nil
+	}
+
+	if len(chatAlerts) == 0 {
+		return "No alerts yet. " + alertUsage, nil
+	}
+
+	var sb strings.Builder
+	var rows [][]tgbotapi.InlineKeyboardButton
+	sb.WriteString("Alerts:\n")
+	for _, al := range chatAlerts {
+		sb.WriteString(fmt.Sprintf("#%d %s\n", al.ID, al.String()))
+
+		data, ok := telegram.CallbackData(alertsCommand, alertsCallbackDelete, strconv.FormatInt(al.ID, 10))
+		if !ok {
+			continue
+		}
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(fmt.Sprintf("❌ #%d %s", al.ID, al.String()), data),
+		))
+	}
+
+	keyboard := tgbotapi.NewInlineKeyboardMarkup(rows...)
+	return sb.String(), &keyboard
+}
+
+func (a *alerts) reply(update *tgbotapi.Update, content string) {
+	a.outCh <- &domain.TextMessage{
+		ChatID:           update.Message.Chat.ID,
+		ReplyToMessageID: update.Message.MessageID,
+		Content:          content,
+	}
+}
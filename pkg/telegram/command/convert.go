@@ -0,0 +1,141 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"github.com/sushkevichd/day-guide-telegram-bot/pkg/domain"
+	"github.com/sushkevichd/day-guide-telegram-bot/pkg/logger"
+	"github.com/sushkevichd/day-guide-telegram-bot/pkg/telegram"
+)
+
+const convertUsage = "Usage: /convert 150 USD to TRY"
+
+type ExchangeRateSnapshotFetcher interface {
+	FetchLatestSnapshot(ctx context.Context) (*domain.ExchangeRateSnapshot, error)
+}
+
+type convert struct {
+	fetcher ExchangeRateSnapshotFetcher
+	maxAge  time.Duration
+	outCh   chan<- domain.Message
+}
+
+// NewConvert creates the /convert command, rates polled with pollInterval are answered with a warning once they are
+// older than the poll interval
+func NewConvert(
+	fetcher ExchangeRateSnapshotFetcher,
+	pollInterval time.Duration,
+	outCh chan<- domain.Message,
+) *convert {
+	return &convert{
+		fetcher: fetcher,
+		maxAge:  pollInterval,
+		outCh:   outCh,
+	}
+}
+
+func (c *convert) Spec() telegram.CommandSpec {
+	return telegram.CommandSpec{
+		Name:        "convert",
+		Description: "Convert an amount with the latest exchange rates, e.g. /convert 150 USD to TRY",
+	}
+}
+
+func (c *convert) Execute(update *tgbotapi.Update, args []string) {
+	amount, pair, err := parseConversion(args)
+	if err != nil {
+		c.reply(update, fmt.Sprintf("%v. %s", err, convertUsage))
+		return
+	}
+
+	response, err := c.convert(context.TODO(), amount, pair)
+	if err != nil {
+		slog.Error("converting currency", "pair", pair, logger.Err(err))
+		response = fmt.Sprintf("Failed to convert: %v", err)
+	}
+
+	c.reply(update, response)
+}
+
+// HandleText answers messages like "150 usd try" and ignores any other text
+func (c *convert) HandleText(update *tgbotapi.Update) bool {
+	amount, pair, err := parseConversion(strings.Fields(update.Message.Text))
+	if err != nil {
+		return false
+	}
+
+	response, err := c.convert(context.TODO(), amount, pair)
+	if err != nil {
+		// Three letter words are not necessarily currencies, e.g. "100 new cat"
+		if !errors.Is(err, domain.ErrUnknownCurrency) {
+			slog.Error("converting currency", "pair", pair, logger.Err(err))
+		}
+		return false
+	}
+
+	c.reply(update, response)
+	return true
+}
+
+func (c *convert) convert(ctx context.Context, amount float64, pair domain.CurrencyPair) (string, error) {
+	snapshot, err := c.fetcher.FetchLatestSnapshot(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	rate, err := snapshot.Rate(pair)
+	if err != nil {
+		return "", err
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("%.2f %s = *%.2f %s*\n", amount, pair.Base, amount*rate.Rate, pair.Quote))
+	sb.WriteString(fmt.Sprintf("1 %s = %.4f %s as of %s UTC", pair.Base, rate.Rate, pair.Quote, rate.Timestamp.UTC().Format("02.01.2006 15:04")))
+
+	if age := time.Since(rate.Timestamp); age > c.maxAge {
+		sb.WriteString(fmt.Sprintf("\n⚠️ Rates were updated %s ago and may be outdated", age.Truncate(time.Minute)))
+	}
+
+	return sb.String(), nil
+}
+
+func (c *convert) reply(update *tgbotapi.Update, content string) {
+	c.outCh <- &domain.TextMessage{
+		ChatID:           update.Message.Chat.ID,
+		ReplyToMessageID: update.Message.MessageID,
+		Content:          content,
+	}
+}
+
+// parseConversion accepts "150 USD to TRY", "150 usd try" and "150 USD/TRY"
+func parseConversion(args []string) (float64, domain.CurrencyPair, error) {
+	if len(args) < 2 {
+		return 0, domain.CurrencyPair{}, fmt.Errorf("expected amount and currencies")
+	}
+
+	amount, err := strconv.ParseFloat(strings.Replace(args[0], ",", ".", 1), 64)
+	if err != nil || amount < 0 || math.IsNaN(amount) || math.IsInf(amount, 0) {
+		return 0, domain.CurrencyPair{}, fmt.Errorf("invalid amount %q", args[0])
+	}
+
+	currencies := args[1:]
+	if len(currencies) == 3 && (strings.EqualFold(currencies[1], "to") || strings.EqualFold(currencies[1], "in")) {
+		currencies = []string{currencies[0], currencies[2]}
+	}
+
+	pair, err := parseCurrencyPair(currencies)
+	if err != nil {
+		return 0, domain.CurrencyPair{}, err
+	}
+
+	return amount, pair, nil
+}
@@ -0,0 +1,65 @@
+package command
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/sushkevichd/day-guide-telegram-bot/pkg/formatter"
+)
+
+// ChatLocationFetcher returns the chat's timezone, the default one for chats not registered yet
+type ChatLocationFetcher interface {
+	FetchLocation(ctx context.Context, chatID int64) (*time.Location, error)
+}
+
+// chatNow returns the current time in the chat's timezone, "today" and "tomorrow" of the chat may differ from the
+// server's ones
+func chatNow(ctx context.Context, locations ChatLocationFetcher, chatID int64) (time.Time, error) {
+	loc, err := locations.FetchLocation(ctx, chatID)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("fetching chat timezone: %v", err)
+	}
+	return time.Now().In(loc), nil
+}
+
+// parseDate accepts "2025-01-15", "15.01.2025" and "15.01" of the current year. "29.02" is rejected in a non-leap
+// year instead of becoming March 1.
+func parseDate(s string, now time.Time) (time.Time, error) {
+	if date, ok := parseFullDate(s); ok {
+		return date, nil
+	}
+
+	month, day, err := parseDayMonth(s)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	date := time.Date(now.Year(), month, day, 0, 0, 0, 0, time.UTC)
+	if date.Month() != month {
+		return time.Time{}, fmt.Errorf("%02d.%02d is not a date in %d", day, month, now.Year())
+	}
+
+	return date, nil
+}
+
+// parseFullDate accepts "2025-01-15" and "15.01.2025"
+func parseFullDate(s string) (time.Time, bool) {
+	for _, layout := range []string{time.DateOnly, "02.01.2006", "2.1.2006"} {
+		if date, err := time.Parse(layout, s); err == nil {
+			return date, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// parseDayMonth accepts "15.01" of any year, including "29.02"
+func parseDayMonth(s string) (time.Month, int, error) {
+	for _, layout := range []string{"02.01", "2.1"} {
+		// Without a year time.Parse takes the leap year 0, so February 29 is valid
+		if date, err := time.Parse(layout, s); err == nil {
+			return date.Month(), date.Day(), nil
+		}
+	}
+	return 0, 0, fmt.Errorf("invalid date %q, use DD.MM.YYYY or DD.MM", formatter.StripMarkdown(s))
+}
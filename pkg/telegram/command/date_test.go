@@ -0,0 +1,75 @@
+package command
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseDate(t *testing.T) {
+	now := time.Date(2025, 5, 1, 23, 30, 0, 0, time.FixedZone("UTC+3", 3*60*60))
+	leapNow := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		s    string
+		now  time.Time
+		want string // empty when the date is rejected
+	}{
+		{"ISO", "2025-01-15", now, "2025-01-15"},
+		{"full", "15.01.2025", now, "2025-01-15"},
+		{"full without leading zeros", "5.1.2025", now, "2025-01-05"},
+		{"full February 29 in a leap year", "29.02.2024", now, "2024-02-29"},
+		{"full February 29 in a common year", "29.02.2025", now, ""},
+		{"day and month of the chat's year", "15.01", now, "2025-01-15"},
+		{"day and month without leading zeros", "1.3", now, "2025-03-01"},
+		{"February 29 of a leap year", "29.02", leapNow, "2024-02-29"},
+		{"February 29 of a common year is not March 1", "29.02", now, ""},
+		{"February 30", "30.02", now, ""},
+		{"month 13", "01.13", now, ""},
+		{"word", "yesterday", now, ""},
+		{"empty", "", now, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseDate(tt.s, tt.now)
+			switch {
+			case tt.want == "" && err == nil:
+				t.Errorf("parseDate(%q) = %s, want an error", tt.s, got.Format(time.DateOnly))
+			case tt.want != "" && err != nil:
+				t.Errorf("parseDate(%q) error: %v, want %s", tt.s, err, tt.want)
+			case err == nil && got.Format(time.DateOnly) != tt.want:
+				t.Errorf("parseDate(%q) = %s, want %s", tt.s, got.Format(time.DateOnly), tt.want)
+			}
+		})
+	}
+}
+
+func TestParseDayMonth(t *testing.T) {
+	tests := []struct {
+		s         string
+		wantMonth time.Month
+		wantDay   int
+		wantErr   bool
+	}{
+		{"15.01", time.January, 15, false},
+		{"1.3", time.March, 1, false},
+		// Without a year time.Parse takes the leap year 0, so the day is accepted for events recurring every 4 years
+		{"29.02", time.February, 29, false},
+		{"30.02", 0, 0, true},
+		{"31.04", 0, 0, true},
+		{"15.01.2025", 0, 0, true},
+		{"15/01", 0, 0, true},
+	}
+
+	for _, tt := range tests {
+		month, day, err := parseDayMonth(tt.s)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseDayMonth(%q) error: %v, want error %v", tt.s, err, tt.wantErr)
+			continue
+		}
+		if month != tt.wantMonth || day != tt.wantDay {
+			t.Errorf("parseDayMonth(%q) = %s %d, want %s %d", tt.s, month, day, tt.wantMonth, tt.wantDay)
+		}
+	}
+}
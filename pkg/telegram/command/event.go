@@ -0,0 +1,215 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"github.com/sushkevichd/day-guide-telegram-bot/pkg/domain"
+	"github.com/sushkevichd/day-guide-telegram-bot/pkg/formatter"
+	"github.com/sushkevichd/day-guide-telegram-bot/pkg/logger"
+	"github.com/sushkevichd/day-guide-telegram-bot/pkg/repository"
+	"github.com/sushkevichd/day-guide-telegram-bot/pkg/telegram"
+)
+
+const eventUsage = "Usage: /event add <DD.MM[.YYYY]> <name> [yearly] [remind <days>], /event list, /event delete <id>"
+
+// Events further ahead are rather announced in a separate reminder
+const maxEventRemindDays = 60
+
+type ChatEventManager interface {
+	Add(ctx context.Context, e domain.ChatEvent) (int64, error)
+	Remove(ctx context.Context, chatID, id int64) error
+	FetchByChatID(ctx context.Context, chatID int64) ([]domain.ChatEvent, error)
+}
+
+type event struct {
+	manager   ChatEventManager
+	locations ChatLocationFetcher
+	outCh     chan<- domain.Message
+}
+
+func NewEvent(
+	manager ChatEventManager,
+	locations ChatLocationFetcher,
+	outCh chan<- domain.Message,
+) *event {
+	return &event{
+		manager:   manager,
+		locations: locations,
+		outCh:     outCh,
+	}
+}
+
+func (e *event) Spec() telegram.CommandSpec {
+	return telegram.CommandSpec{
+		Name:        "event",
+		Aliases:     []string{"events"},
+		Description: "Chat events shown with the holidays, e.g. /event add 14.03 Birthday of Anna yearly remind 3",
+	}
+}
+
+func (e *event) Execute(update *tgbotapi.Update, args []string) {
+	ctx := context.TODO()
+	chatID := update.Message.Chat.ID
+
+	if len(args) == 0 {
+		args = []string{"list"}
+	}
+
+	switch strings.ToLower(args[0]) {
+	case "add":
+		e.add(ctx, update, args[1:])
+	case "list":
+		e.reply(update, e.list(ctx, chatID))
+	case "delete":
+		if len(args) != 2 {
+			e.reply(update, eventUsage)
+			return
+		}
+		id, err := strconv.ParseInt(strings.TrimPrefix(args[1], "#"), 10, 64)
+		if err != nil {
+			e.reply(update, eventUsage)
+			return
+		}
+
+		msg := fmt.Sprintf("Event #%d deleted", id)
+		if err := e.manager.Remove(ctx, chatID, id); err != nil {
+			if errors.Is(err, repository.ErrChatEventNotFound) {
+				msg = fmt.Sprintf("Event #%d not found", id)
+			} else {
+				slog.Error("removing chat event", "chatID", chatID, "id", id, logger.Err(err))
+				msg = "Failed to delete event"
+			}
+		}
+		e.reply(update, msg)
+	default:
+		e.reply(update, eventUsage)
+	}
+}
+
+func (e *event) add(ctx context.Context, update *tgbotapi.Update, args []string) {
+	chatID := update.Message.Chat.ID
+
+	now, err := chatNow(ctx, e.locations, chatID)
+	if err != nil {
+		slog.Error("resolving chat time", "chatID", chatID, logger.Err(err))
+		e.reply(update, "Failed to add event")
+		return
+	}
+
+	newEvent, err := parseChatEvent(args, now)
+	if err != nil {
+		e.reply(update, fmt.Sprintf("%v. %s", err, eventUsage))
+		return
+	}
+	newEvent.ChatID = chatID
+
+	id, err := e.manager.Add(ctx, newEvent)
+	if err != nil {
+		slog.Error("adding chat event", "chatID", chatID, "event", newEvent.String(), logger.Err(err))
+
+		msg := "Failed to add event"
+		if errors.Is(err, repository.ErrChatNotRegistered) {
+			msg = "Register the chat with /register first"
+		}
+		e.reply(update, msg)
+		return
+	}
+
+	e.reply(update, fmt.Sprintf("Event #%d added: %s", id, newEvent.String()))
+}
+
+// parseChatEvent parses "<date> <name> [yearly] [remind <days>]", the options are taken from the end of the name. A
+// one-time event given without a year falls on its next date.
+func parseChatEvent(args []string, now time.Time) (domain.ChatEvent, error) {
+	if len(args) < 2 {
+		return domain.ChatEvent{}, errors.New("expected date and name")
+	}
+
+	// The date isn't resolved to the current year, 29.02 is kept for the next leap year
+	var ev domain.ChatEvent
+	date, withYear := parseFullDate(args[0])
+	if withYear {
+		ev.Month, ev.Day = date.Month(), date.Day()
+	} else {
+		var err error
+		if ev.Month, ev.Day, err = parseDayMonth(args[0]); err != nil {
+			return domain.ChatEvent{}, err
+		}
+	}
+
+	name := args[1:]
+	for len(name) > 1 {
+		last := strings.ToLower(strings.Trim(name[len(name)-1], "[]"))
+		switch {
+		case last == "yearly":
+			ev.Yearly = true
+			name = name[:len(name)-1]
+			continue
+		case len(name) > 2 && strings.ToLower(strings.Trim(name[len(name)-2], "[]")) == "remind":
+			days, err := strconv.Atoi(last)
+			if err != nil || days < 0 || days > maxEventRemindDays {
+				return domain.ChatEvent{}, fmt.Errorf("invalid reminder %q, expected 0-%d days", last, maxEventRemindDays)
+			}
+			ev.RemindDays = days
+			name = name[:len(name)-2]
+			continue
+		}
+		break
+	}
+
+	// The name is shown in Markdown messages
+	ev.Name = formatter.StripMarkdown(strings.Join(name, " "))
+	if strings.TrimSpace(ev.Name) == "" {
+		return domain.ChatEvent{}, errors.New("expected name")
+	}
+
+	switch {
+	case withYear:
+		ev.Year = date.Year()
+	case !ev.Yearly:
+		next, _ := domain.HolidayRecurrence{Rule: domain.HolidayRuleFixed, Month: ev.Month, Day: ev.Day}.NextDate(now)
+		ev.Year = next.Year()
+	}
+
+	if _, ok := ev.NextDate(now); !ok {
+		return domain.ChatEvent{}, fmt.Errorf("date %s is in the past, add yearly to repeat it", args[0])
+	}
+
+	return ev, nil
+}
+
+func (e *event) list(ctx context.Context, chatID int64) string {
+	events, err := e.manager.FetchByChatID(ctx, chatID)
+	if err != nil {
+		slog.Error("fetching chat events", "chatID", chatID, logger.Err(err))
+		return "Failed to fetch events"
+	}
+
+	if len(events) == 0 {
+		return "No events yet. " + eventUsage
+	}
+
+	var sb strings.Builder
+	sb.WriteString("Events:\n")
+	for _, ev := range events {
+		sb.WriteString(fmt.Sprintf("#%d %s\n", ev.ID, ev.String()))
+	}
+
+	return sb.String()
+}
+
+func (e *event) reply(update *tgbotapi.Update, content string) {
+	e.outCh <- &domain.TextMessage{
+		ChatID:           update.Message.Chat.ID,
+		ReplyToMessageID: update.Message.MessageID,
+		Content:          content,
+	}
+}
@@ -3,15 +3,32 @@ package command
 import (
 	"context"
 	"fmt"
+	"log/slog"
+	"strconv"
 	"strings"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 
 	"github.com/sushkevichd/day-guide-telegram-bot/pkg/domain"
+	"github.com/sushkevichd/day-guide-telegram-bot/pkg/logger"
+	"github.com/sushkevichd/day-guide-telegram-bot/pkg/telegram"
 )
 
+const exchangeRateCommand = "rate"
+
+// Chart periods selectable with the inline keyboard under the /rate image
+var exchangeRatePeriods = []struct {
+	label string
+	days  int
+}{
+	{"7d", 7},
+	{"30d", 30},
+	{"90d", 90},
+	{"1y", 365},
+}
+
 type ExchangeRateReportGenerator interface {
-	Generate(ctx context.Context, pair domain.CurrencyPair) ([]byte, string, error)
+	Generate(ctx context.Context, pair domain.CurrencyPair, days int) ([]byte, string, error)
 }
 
 type exchangeRate struct {
@@ -32,13 +49,31 @@ func NewExchangeRate(
 	}
 }
 
-func (e *exchangeRate) CanExecute(update *tgbotapi.Update) bool {
-	return update.Message != nil && strings.HasPrefix(update.Message.Text, "/rate")
+func (e *exchangeRate) Spec() telegram.CommandSpec {
+	return telegram.CommandSpec{
+		Name:        exchangeRateCommand,
+		Aliases:     []string{"rates"},
+		Description: "Exchange rate chart for any currency pair, e.g. /rate EUR TRY",
+	}
 }
 
-func (e *exchangeRate) Execute(update *tgbotapi.Update) {
-	for _, pair := range e.pairs {
-		imageBytes, caption, err := e.reportGenerator.Generate(context.TODO(), pair)
+func (e *exchangeRate) Execute(update *tgbotapi.Update, args []string) {
+	pairs := e.pairs
+	if len(args) > 0 {
+		pair, err := parseCurrencyPair(args)
+		if err != nil {
+			e.outCh <- &domain.TextMessage{
+				ChatID:           update.Message.Chat.ID,
+				ReplyToMessageID: update.Message.MessageID,
+				Content:          fmt.Sprintf("%v. Usage: /rate, /rate EUR TRY", err),
+			}
+			return
+		}
+		pairs = []domain.CurrencyPair{pair}
+	}
+
+	for _, pair := range pairs {
+		imageBytes, caption, err := e.reportGenerator.Generate(context.TODO(), pair, domain.DefaultExchangeRatePeriod)
 		if err != nil {
 			e.outCh <- &domain.TextMessage{
 				ChatID:           update.Message.Chat.ID,
@@ -53,6 +88,80 @@ func (e *exchangeRate) Execute(update *tgbotapi.Update) {
 			ReplyToMessageID: update.Message.MessageID,
 			Content:          imageBytes,
 			Caption:          caption,
+			ReplyMarkup:      periodKeyboard(pair, domain.DefaultExchangeRatePeriod),
+		}
+	}
+}
+
+// HandleCallback redraws the chart for the period selected with the inline keyboard
+func (e *exchangeRate) HandleCallback(query *tgbotapi.CallbackQuery, args []string) {
+	if len(args) != 3 {
+		slog.Warn("invalid exchange rate callback", "data", query.Data)
+		return
+	}
+
+	days, err := strconv.Atoi(args[0])
+	if err != nil {
+		slog.Warn("invalid exchange rate callback", "data", query.Data, logger.Err(err))
+		return
+	}
+	pair, err := parseCurrencyPair(args[1:])
+	if err != nil {
+		slog.Warn("invalid exchange rate callback", "data", query.Data, logger.Err(err))
+		return
+	}
+
+	imageBytes, caption, err := e.reportGenerator.Generate(context.TODO(), pair, days)
+	if err != nil {
+		slog.Error("generating exchange rate report", "pair", pair, "days", days, logger.Err(err))
+		return
+	}
+
+	e.outCh <- &domain.EditImageMessage{
+		ChatID:      query.Message.Chat.ID,
+		MessageID:   query.Message.MessageID,
+		Content:     imageBytes,
+		Caption:     caption,
+		ReplyMarkup: periodKeyboard(pair, days),
+	}
+}
+
+func periodKeyboard(pair domain.CurrencyPair, selectedDays int) *tgbotapi.InlineKeyboardMarkup {
+	var row []tgbotapi.InlineKeyboardButton
+	for _, p := range exchangeRatePeriods {
+		label := p.label
+		if p.days == selectedDays {
+			label = "• " + label
+		}
+
+		data, ok := telegram.CallbackData(exchangeRateCommand, strconv.Itoa(p.days), pair.Base.String(), pair.Quote.String())
+		if !ok {
+			continue
 		}
+		row = append(row, tgbotapi.NewInlineKeyboardButtonData(label, data))
 	}
+
+	keyboard := tgbotapi.NewInlineKeyboardMarkup(row)
+	return &keyboard
+}
+
+// parseCurrencyPair accepts "EUR TRY" and "EUR/TRY"
+func parseCurrencyPair(args []string) (domain.CurrencyPair, error) {
+	if len(args) == 1 {
+		args = strings.Split(args[0], "/")
+	}
+	if len(args) != 2 {
+		return domain.CurrencyPair{}, fmt.Errorf("expected two currencies")
+	}
+
+	base, err := domain.ParseCurrency(args[0])
+	if err != nil {
+		return domain.CurrencyPair{}, err
+	}
+	quote, err := domain.ParseCurrency(args[1])
+	if err != nil {
+		return domain.CurrencyPair{}, err
+	}
+
+	return domain.CurrencyPair{Base: base, Quote: quote}, nil
 }
@@ -0,0 +1,68 @@
+package command
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"github.com/sushkevichd/day-guide-telegram-bot/pkg/domain"
+	"github.com/sushkevichd/day-guide-telegram-bot/pkg/telegram"
+)
+
+const defaultForecastDays = 3
+
+type ForecastReportGenerator interface {
+	Generate(ctx context.Context, chatID int64, city string, days int) (string, error)
+}
+
+type forecast struct {
+	reportGenerator ForecastReportGenerator
+	outCh           chan<- domain.Message
+}
+
+func NewForecast(
+	reportGenerator ForecastReportGenerator,
+	outCh chan<- domain.Message,
+) *forecast {
+	return &forecast{
+		reportGenerator: reportGenerator,
+		outCh:           outCh,
+	}
+}
+
+func (f *forecast) Spec() telegram.CommandSpec {
+	return telegram.CommandSpec{
+		Name:        "forecast",
+		Description: fmt.Sprintf("Weather forecast for up to %d days, e.g. /forecast Анталья 5", domain.MaxForecastDays),
+	}
+}
+
+func (f *forecast) Execute(update *tgbotapi.Update, args []string) {
+	days := defaultForecastDays
+	if len(args) > 0 {
+		if n, err := strconv.Atoi(args[len(args)-1]); err == nil {
+			days = n
+			args = args[:len(args)-1]
+		}
+	}
+
+	var response string
+	if days < 1 || days > domain.MaxForecastDays {
+		response = fmt.Sprintf("Forecast is available for 1 to %d days", domain.MaxForecastDays)
+	} else {
+		var err error
+		response, err = f.reportGenerator.Generate(context.TODO(), update.Message.Chat.ID, strings.Join(args, " "), days)
+		if err != nil {
+			response = fmt.Sprintf("Failed to generate forecast: %v", err)
+		}
+	}
+
+	f.outCh <- &domain.TextMessage{
+		ChatID:           update.Message.Chat.ID,
+		ReplyToMessageID: update.Message.MessageID,
+		Content:          response,
+	}
+}
@@ -2,15 +2,17 @@ package command
 
 import (
 	"context"
+	"fmt"
 	"strings"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 	"github.com/sushkevichd/day-guide-telegram-bot/pkg/domain"
+	"github.com/sushkevichd/day-guide-telegram-bot/pkg/telegram"
 )
 
 type HackerNewsService interface {
-	GetNews(limit int) ([]domain.NewsItem, error)
-	GetNewsAsText(limit int) (string, error)
+	GetNews(ctx context.Context, list domain.NewsList, limit int) ([]domain.NewsItem, error)
+	GetNewsAsText(ctx context.Context, list domain.NewsList, limit int) (string, error)
 }
 
 type AIClient interface {
@@ -40,19 +42,33 @@ func NewGetHackerNews(
 	}
 }
 
-func (g *getHackerNews) CanExecute(update *tgbotapi.Update) bool {
-	return update.Message != nil && strings.HasPrefix(update.Message.Text, "/news")
+func (g *getHackerNews) Spec() telegram.CommandSpec {
+	return telegram.CommandSpec{
+		Name:        "news",
+		Aliases:     []string{"hn"},
+		Description: "Hacker News digest, /news top|best|new|ask|show",
+	}
 }
 
-func (g *getHackerNews) Execute(update *tgbotapi.Update) {
+func (g *getHackerNews) Execute(update *tgbotapi.Update, args []string) {
 	ctx := context.Background()
-	text, err := g.service.GetNewsAsText(10)
+
+	list := domain.NewsTop
+	if len(args) > 0 {
+		var err error
+		if list, err = domain.ParseNewsList(args[0]); err != nil {
+			g.telegramClient.SendResponse(ctx, update.Message.Chat.ID, fmt.Sprintf("%v. Usage: /news %s", err, newsListsUsage()))
+			return
+		}
+	}
+
+	text, err := g.service.GetNewsAsText(ctx, list, 10)
 	if err != nil {
 		g.telegramClient.SendError(ctx, update.Message.Chat.ID, err)
 		return
 	}
 
-	resp, err := g.aiClient.GenerateResponse(context.Background(), []domain.GMessage{
+	resp, err := g.aiClient.GenerateResponse(ctx, []domain.GMessage{
 		{
 			Role: "user",
 			Parts: []domain.GMessagePart{
@@ -72,3 +88,11 @@ func (g *getHackerNews) Execute(update *tgbotapi.Update) {
 
 	g.telegramClient.SendResponse(ctx, update.Message.Chat.ID, resp.Parts[0].Text)
 }
+
+func newsListsUsage() string {
+	var lists []string
+	for _, l := range domain.NewsLists() {
+		lists = append(lists, string(l))
+	}
+	return strings.Join(lists, "|")
+}
@@ -2,46 +2,205 @@ package command
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log/slog"
+	"strconv"
 	"strings"
+	"time"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 
 	"github.com/sushkevichd/day-guide-telegram-bot/pkg/domain"
+	"github.com/sushkevichd/day-guide-telegram-bot/pkg/logger"
+	"github.com/sushkevichd/day-guide-telegram-bot/pkg/repository"
+	"github.com/sushkevichd/day-guide-telegram-bot/pkg/telegram"
+)
+
+const (
+	holidayCommand                = "holiday"
+	holidayCallbackToggleCategory = "category"
+
+	holidayUsage = "Usage: /holiday, /holiday tomorrow, /holiday week, /holiday <date>, /holiday search <text>, /holiday categories"
 )
 
 type HolidayReportGenerator interface {
-	Generate(ctx context.Context) (string, error)
+	Generate(ctx context.Context, chatID int64, now time.Time) (string, error)
+	GenerateForDate(ctx context.Context, chatID int64, date time.Time) (string, error)
+	GenerateWeek(ctx context.Context, chatID int64, from time.Time) (string, error)
+	Search(ctx context.Context, chatID int64, text string, now time.Time) (string, error)
+}
+
+type HolidayCategoryManager interface {
+	FetchAll(ctx context.Context) ([]domain.HolidayCategory, error)
+	FetchHiddenCategoryIDs(ctx context.Context, chatID int64) ([]int64, error)
+	SetHidden(ctx context.Context, chatID, categoryID int64, hidden bool) error
 }
 
 type holiday struct {
 	reportGenerator HolidayReportGenerator
+	categories      HolidayCategoryManager
+	locations       ChatLocationFetcher
 	outCh           chan<- domain.Message
 }
 
 func NewHoliday(
 	reportGenerator HolidayReportGenerator,
+	categories HolidayCategoryManager,
+	locations ChatLocationFetcher,
 	outCh chan<- domain.Message,
 ) *holiday {
 	return &holiday{
 		reportGenerator: reportGenerator,
+		categories:      categories,
+		locations:       locations,
 		outCh:           outCh,
 	}
 }
 
-func (_ *holiday) CanExecute(update *tgbotapi.Update) bool {
-	return update.Message != nil && strings.HasPrefix(update.Message.Text, "/holiday")
+func (_ *holiday) Spec() telegram.CommandSpec {
+	return telegram.CommandSpec{
+		Name:        holidayCommand,
+		Aliases:     []string{"holidays"},
+		Description: "Today's holidays, /holiday tomorrow|week|<DD.MM.YYYY>, /holiday search <text>, /holiday categories",
+	}
 }
 
-func (h *holiday) Execute(update *tgbotapi.Update) {
-	response, err := h.reportGenerator.Generate(context.TODO())
+func (h *holiday) Execute(update *tgbotapi.Update, args []string) {
+	ctx := context.TODO()
+	chatID := update.Message.Chat.ID
+
+	now, err := chatNow(ctx, h.locations, chatID)
+	if err != nil {
+		h.outCh <- &domain.TextMessage{
+			ChatID:           chatID,
+			ReplyToMessageID: update.Message.MessageID,
+			Content:          fmt.Sprintf("Failed to generate holidays report: %v", err),
+		}
+		return
+	}
+
+	var response string
+	switch {
+	case len(args) == 0:
+		response, err = h.reportGenerator.Generate(ctx, chatID, now)
+	case len(args) == 1 && args[0] == "categories":
+		content, keyboard := h.categoriesKeyboard(ctx, chatID)
+		h.outCh <- &domain.TextMessage{
+			ChatID:           chatID,
+			ReplyToMessageID: update.Message.MessageID,
+			Content:          content,
+			ReplyMarkup:      keyboard,
+		}
+		return
+	case args[0] == "search":
+		text := strings.Join(args[1:], " ")
+		if text == "" {
+			response = holidayUsage
+			break
+		}
+		response, err = h.reportGenerator.Search(ctx, chatID, text, now)
+	case len(args) > 1:
+		response = holidayUsage
+	case args[0] == "tomorrow":
+		response, err = h.reportGenerator.GenerateForDate(ctx, chatID, now.AddDate(0, 0, 1))
+	case args[0] == "week":
+		response, err = h.reportGenerator.GenerateWeek(ctx, chatID, now)
+	default:
+		date, parseErr := parseDate(args[0], now)
+		if parseErr != nil {
+			response = fmt.Sprintf("%v. %s", parseErr, holidayUsage)
+			break
+		}
+		response, err = h.reportGenerator.GenerateForDate(ctx, chatID, date)
+	}
 	if err != nil {
 		response = fmt.Sprintf("Failed to generate holidays report: %v", err)
 	}
 
 	h.outCh <- &domain.TextMessage{
-		ChatID:           update.Message.Chat.ID,
+		ChatID:           chatID,
 		ReplyToMessageID: update.Message.MessageID,
 		Content:          response,
 	}
 }
+
+// HandleCallback shows or hides the category picked with the inline keyboard and refreshes it
+func (h *holiday) HandleCallback(query *tgbotapi.CallbackQuery, args []string) {
+	ctx := context.TODO()
+	chatID := query.Message.Chat.ID
+
+	if len(args) != 3 || args[0] != holidayCallbackToggleCategory {
+		slog.Warn("invalid holiday callback", "data", query.Data)
+		return
+	}
+
+	categoryID, err := strconv.ParseInt(args[1], 10, 64)
+	if err != nil {
+		slog.Warn("invalid holiday callback", "data", query.Data, logger.Err(err))
+		return
+	}
+
+	// The button carries the new state, so a double tap doesn't toggle the category back
+	hidden := args[2] == "hide"
+	content := ""
+	switch err := h.categories.SetHidden(ctx, chatID, categoryID, hidden); {
+	case err == nil:
+	case errors.Is(err, repository.ErrChatNotRegistered):
+		content = "Register the chat with /register first"
+	case errors.Is(err, repository.ErrHolidayCategoryNotFound):
+		// The button outlived the category, the refreshed keyboard drops it
+		slog.Warn("hiding unknown holiday category", "chatID", chatID, "categoryID", categoryID)
+	default:
+		slog.Error("setting hidden holiday category", "chatID", chatID, "categoryID", categoryID, logger.Err(err))
+		return
+	}
+
+	keyboardContent, keyboard := h.categoriesKeyboard(ctx, chatID)
+	if content == "" {
+		content = keyboardContent
+	}
+	h.outCh <- &domain.EditTextMessage{
+		ChatID:      chatID,
+		MessageID:   query.Message.MessageID,
+		Content:     content,
+		ReplyMarkup: keyboard,
+	}
+}
+
+func (h *holiday) categoriesKeyboard(ctx context.Context, chatID int64) (string, *tgbotapi.InlineKeyboardMarkup) {
+	categories, err := h.categories.FetchAll(ctx)
+	if err != nil {
+		slog.Error("fetching holiday categories", logger.Err(err))
+		return "Failed to fetch holiday categories", nil
+	}
+
+	hiddenIDs, err := h.categories.FetchHiddenCategoryIDs(ctx, chatID)
+	if err != nil {
+		slog.Error("fetching hidden holiday categories", "chatID", chatID, logger.Err(err))
+		return "Failed to fetch holiday categories", nil
+	}
+	hidden := map[int64]bool{}
+	for _, id := range hiddenIDs {
+		hidden[id] = true
+	}
+
+	var rows [][]tgbotapi.InlineKeyboardButton
+	for _, c := range categories {
+		mark, action := "✅", "hide"
+		if hidden[c.ID] {
+			mark, action = "⬜", "show"
+		}
+
+		data, ok := telegram.CallbackData(holidayCommand, holidayCallbackToggleCategory, strconv.FormatInt(c.ID, 10), action)
+		if !ok {
+			continue
+		}
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(fmt.Sprintf("%s %s %s", mark, c.Emoji, c.Name), data),
+		))
+	}
+
+	keyboard := tgbotapi.NewInlineKeyboardMarkup(rows...)
+	return "Holiday categories shown in this chat, tap to toggle:", &keyboard
+}
@@ -3,44 +3,87 @@ package command
 import (
 	"context"
 	"fmt"
-	"strings"
+	"time"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 
 	"github.com/sushkevichd/day-guide-telegram-bot/pkg/domain"
+	"github.com/sushkevichd/day-guide-telegram-bot/pkg/telegram"
 )
 
 type MoonPhaseReportGenerator interface {
-	Generate(ctx context.Context) (string, error)
+	Generate(ctx context.Context, chatID int64, now time.Time) (string, error)
+}
+
+type MoonCalendarGenerator interface {
+	Generate(ctx context.Context, chatID int64) (string, error)
+	GenerateForDate(ctx context.Context, chatID int64, date time.Time) (string, error)
 }
 
 type moonPhase struct {
-	reportGenerator WeatherReportGenerator
-	outCh           chan<- domain.Message
+	reportGenerator   MoonPhaseReportGenerator
+	calendarGenerator MoonCalendarGenerator
+	locations         ChatLocationFetcher
+	outCh             chan<- domain.Message
 }
 
 func NewMoonPhase(
-	reportGenerator WeatherReportGenerator,
+	reportGenerator MoonPhaseReportGenerator,
+	calendarGenerator MoonCalendarGenerator,
+	locations ChatLocationFetcher,
 	outCh chan<- domain.Message,
 ) *moonPhase {
 	return &moonPhase{
-		reportGenerator: reportGenerator,
-		outCh:           outCh,
+		reportGenerator:   reportGenerator,
+		calendarGenerator: calendarGenerator,
+		locations:         locations,
+		outCh:             outCh,
 	}
 }
 
-func (m *moonPhase) CanExecute(update *tgbotapi.Update) bool {
-	return update.Message != nil && strings.HasPrefix(update.Message.Text, "/moon")
+func (m *moonPhase) Spec() telegram.CommandSpec {
+	return telegram.CommandSpec{
+		Name:        "moon",
+		Description: "Moon phase and lunar day, /moon calendar for upcoming phases, /moon <DD.MM.YYYY> for a date",
+	}
 }
 
-func (m *moonPhase) Execute(update *tgbotapi.Update) {
-	response, err := m.reportGenerator.Generate(context.TODO())
+func (m *moonPhase) Execute(update *tgbotapi.Update, args []string) {
+	ctx := context.TODO()
+	chatID := update.Message.Chat.ID
+
+	now, err := chatNow(ctx, m.locations, chatID)
+	if err != nil {
+		m.outCh <- &domain.TextMessage{
+			ChatID:           chatID,
+			ReplyToMessageID: update.Message.MessageID,
+			Content:          fmt.Sprintf("Failed to generate moon phase report: %v", err),
+		}
+		return
+	}
+
+	var response string
+	switch {
+	case len(args) == 0:
+		response, err = m.reportGenerator.Generate(ctx, chatID, now)
+	case len(args) == 1 && args[0] == "calendar":
+		response, err = m.calendarGenerator.Generate(ctx, chatID)
+	case len(args) == 1:
+		date, parseErr := parseDate(args[0], now)
+		if parseErr != nil {
+			response = fmt.Sprintf("%v. Usage: /moon, /moon calendar, /moon <date>", parseErr)
+			break
+		}
+		response, err = m.calendarGenerator.GenerateForDate(ctx, chatID, date)
+	default:
+		response = "Usage: /moon, /moon calendar, /moon <date>"
+	}
 	if err != nil {
 		response = fmt.Sprintf("Failed to generate moon phase report: %v", err)
 	}
 
 	m.outCh <- &domain.TextMessage{
-		ChatID:           update.Message.Chat.ID,
+		ChatID:           chatID,
 		ReplyToMessageID: update.Message.MessageID,
 		Content:          response,
 	}
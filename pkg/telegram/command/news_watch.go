@@ -0,0 +1,168 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"github.com/sushkevichd/day-guide-telegram-bot/pkg/domain"
+	"github.com/sushkevichd/day-guide-telegram-bot/pkg/formatter"
+	"github.com/sushkevichd/day-guide-telegram-bot/pkg/logger"
+	"github.com/sushkevichd/day-guide-telegram-bot/pkg/repository"
+	"github.com/sushkevichd/day-guide-telegram-bot/pkg/telegram"
+)
+
+const newsWatchUsage = "Usage: /hnwatch add <keywords> [> <score>], /hnwatch list, /hnwatch delete <id>"
+
+type NewsWatchManager interface {
+	Add(ctx context.Context, w domain.NewsWatch) (int64, error)
+	Remove(ctx context.Context, chatID, id int64) error
+	FetchByChatID(ctx context.Context, chatID int64) ([]domain.NewsWatch, error)
+}
+
+type newsWatch struct {
+	manager NewsWatchManager
+	outCh   chan<- domain.Message
+}
+
+func NewNewsWatch(
+	manager NewsWatchManager,
+	outCh chan<- domain.Message,
+) *newsWatch {
+	return &newsWatch{
+		manager: manager,
+		outCh:   outCh,
+	}
+}
+
+func (n *newsWatch) Spec() telegram.CommandSpec {
+	return telegram.CommandSpec{
+		Name:        "hnwatch",
+		Description: "Notify the chat about Hacker News stories with keywords, e.g. /hnwatch add golang postgres > 200",
+	}
+}
+
+func (n *newsWatch) Execute(update *tgbotapi.Update, args []string) {
+	ctx := context.TODO()
+	chatID := update.Message.Chat.ID
+
+	if len(args) == 0 {
+		args = []string{"list"}
+	}
+
+	switch strings.ToLower(args[0]) {
+	case "add":
+		n.add(ctx, update, args[1:])
+	case "list":
+		n.reply(update, n.list(ctx, chatID))
+	case "delete":
+		if len(args) != 2 {
+			n.reply(update, newsWatchUsage)
+			return
+		}
+		id, err := strconv.ParseInt(strings.TrimPrefix(args[1], "#"), 10, 64)
+		if err != nil {
+			n.reply(update, newsWatchUsage)
+			return
+		}
+
+		msg := fmt.Sprintf("Watch #%d deleted", id)
+		if err := n.manager.Remove(ctx, chatID, id); err != nil {
+			if errors.Is(err, repository.ErrNewsWatchNotFound) {
+				msg = fmt.Sprintf("Watch #%d not found", id)
+			} else {
+				slog.Error("removing news watch", "chatID", chatID, "id", id, logger.Err(err))
+				msg = "Failed to delete watch"
+			}
+		}
+		n.reply(update, msg)
+	default:
+		n.reply(update, newsWatchUsage)
+	}
+}
+
+func (n *newsWatch) add(ctx context.Context, update *tgbotapi.Update, args []string) {
+	chatID := update.Message.Chat.ID
+
+	watch, err := parseNewsWatch(args)
+	if err != nil {
+		n.reply(update, fmt.Sprintf("%v. %s", err, newsWatchUsage))
+		return
+	}
+	watch.ChatID = chatID
+
+	id, err := n.manager.Add(ctx, watch)
+	if err != nil {
+		slog.Error("adding news watch", "chatID", chatID, "watch", watch.String(), logger.Err(err))
+
+		msg := "Failed to add watch"
+		if errors.Is(err, repository.ErrChatNotRegistered) {
+			msg = "Register the chat with /register first"
+		}
+		n.reply(update, msg)
+		return
+	}
+
+	n.reply(update, fmt.Sprintf("Watch #%d added: %s", id, watch.String()))
+}
+
+// parseNewsWatch parses "golang postgres" and "golang postgres > 200"
+func parseNewsWatch(args []string) (domain.NewsWatch, error) {
+	watch := domain.NewsWatch{Threshold: domain.DefaultNewsWatchThreshold}
+
+	if len(args) >= 2 && args[len(args)-2] == ">" {
+		threshold, err := strconv.Atoi(args[len(args)-1])
+		if err != nil || threshold < 0 {
+			return domain.NewsWatch{}, fmt.Errorf("invalid score %q", args[len(args)-1])
+		}
+		watch.Threshold = threshold
+		args = args[:len(args)-2]
+	}
+
+	// Keywords are shown in Markdown messages
+	for _, arg := range args {
+		keyword := strings.ToLower(formatter.StripMarkdown(arg))
+		if keyword != "" && !slices.Contains(watch.Keywords, keyword) {
+			watch.Keywords = append(watch.Keywords, keyword)
+		}
+	}
+	if len(watch.Keywords) == 0 {
+		return domain.NewsWatch{}, errors.New("expected keywords")
+	}
+
+	return watch, nil
+}
+
+func (n *newsWatch) list(ctx context.Context, chatID int64) string {
+	watches, err := n.manager.FetchByChatID(ctx, chatID)
+	if err != nil {
+		slog.Error("fetching news watches", "chatID", chatID, logger.Err(err))
+		return "Failed to fetch watches"
+	}
+
+	if len(watches) == 0 {
+		return "No watches yet. " + newsWatchUsage
+	}
+
+	var sb strings.Builder
+	sb.WriteString("Hacker News watches:\n")
+	for _, w := range watches {
+		sb.WriteString(fmt.Sprintf("#%d %s\n", w.ID, w.String()))
+	}
+
+	return sb.String()
+}
+
+func (n *newsWatch) reply(update *tgbotapi.Update, content string) {
+	n.outCh <- &domain.TextMessage{
+		ChatID:           update.Message.Chat.ID,
+		ReplyToMessageID: update.Message.MessageID,
+		Content:          content,
+	}
+}
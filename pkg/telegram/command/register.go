@@ -4,13 +4,13 @@ import (
 	"context"
 	"errors"
 	"log/slog"
-	"strings"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 
 	"github.com/sushkevichd/day-guide-telegram-bot/pkg/domain"
 	"github.com/sushkevichd/day-guide-telegram-bot/pkg/logger"
 	"github.com/sushkevichd/day-guide-telegram-bot/pkg/repository"
+	"github.com/sushkevichd/day-guide-telegram-bot/pkg/telegram"
 )
 
 type Saver interface {
@@ -32,12 +32,15 @@ func NewRegister(
 	}
 }
 
-func (r *register) CanExecute(update *tgbotapi.Update) bool {
-	return update.Message != nil && strings.HasPrefix(update.Message.Text, "/register")
+func (r *register) Spec() telegram.CommandSpec {
+	return telegram.CommandSpec{
+		Name:        "register",
+		Description: "Register the chat for daily reports",
+	}
 }
 
-func (r *register) Execute(update *tgbotapi.Update) {
-	msg := "Registration completed"
+func (r *register) Execute(update *tgbotapi.Update, args []string) {
+	msg := "Registration completed. Use /subscribe <topic> to receive daily reports"
 
 	chat := &domain.Chat{
 		ID:           update.Message.Chat.ID,
@@ -0,0 +1,150 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"github.com/sushkevichd/day-guide-telegram-bot/pkg/domain"
+	"github.com/sushkevichd/day-guide-telegram-bot/pkg/logger"
+	"github.com/sushkevichd/day-guide-telegram-bot/pkg/repository"
+	"github.com/sushkevichd/day-guide-telegram-bot/pkg/telegram"
+)
+
+const settingsUsage = "Usage: /settings, /settings timezone <IANA name>, /settings time <topic> <HH:MM>[,HH:MM], /settings forecast on|off"
+
+type ChatSettingsManager interface {
+	FetchByID(ctx context.Context, chatID int64) (*domain.Chat, error)
+	SetTimezone(ctx context.Context, chatID int64, timezone string) error
+	SetWeatherForecast(ctx context.Context, chatID int64, enabled bool) error
+}
+
+type DeliveryTimeSetter interface {
+	SetDeliveryTimes(ctx context.Context, chatID int64, topic domain.Topic, times []domain.DeliveryTime) error
+}
+
+type settings struct {
+	chats         ChatSettingsManager
+	subscriptions DeliveryTimeSetter
+	outCh         chan<- domain.Message
+}
+
+func NewSettings(
+	chats ChatSettingsManager,
+	subscriptions DeliveryTimeSetter,
+	outCh chan<- domain.Message,
+) *settings {
+	return &settings{
+		chats:         chats,
+		subscriptions: subscriptions,
+		outCh:         outCh,
+	}
+}
+
+func (s *settings) Spec() telegram.CommandSpec {
+	return telegram.CommandSpec{
+		Name:        "settings",
+		Description: "Chat timezone, delivery times and morning weather",
+	}
+}
+
+func (s *settings) Execute(update *tgbotapi.Update, args []string) {
+	ctx := context.TODO()
+	chatID := update.Message.Chat.ID
+
+	var response string
+	switch {
+	case len(args) == 0:
+		response = s.show(ctx, chatID)
+	case args[0] == "timezone" && len(args) == 2:
+		response = s.setTimezone(ctx, chatID, args[1])
+	case args[0] == "time" && len(args) == 3:
+		response = s.setTime(ctx, chatID, args[1], args[2])
+	case args[0] == "forecast" && len(args) == 2 && (args[1] == "on" || args[1] == "off"):
+		response = s.setWeatherForecast(ctx, chatID, args[1] == "on")
+	default:
+		response = settingsUsage
+	}
+
+	s.outCh <- &domain.TextMessage{
+		ChatID:           chatID,
+		ReplyToMessageID: update.Message.MessageID,
+		Content:          response,
+	}
+}
+
+func (s *settings) show(ctx context.Context, chatID int64) string {
+	chat, err := s.chats.FetchByID(ctx, chatID)
+	if err != nil {
+		if errors.Is(err, repository.ErrChatNotRegistered) {
+			return "Register the chat with /register first"
+		}
+		slog.Error("fetching chat", "chatID", chatID, logger.Err(err))
+		return "Failed to fetch settings"
+	}
+
+	morningWeather := "current weather"
+	if chat.WeatherForecast {
+		morningWeather = "forecast for the day"
+	}
+
+	return fmt.Sprintf("Timezone: `%s`\nMorning weather: %s\n%s", chat.Timezone, morningWeather, settingsUsage)
+}
+
+func (s *settings) setTimezone(ctx context.Context, chatID int64, timezone string) string {
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		return fmt.Sprintf("Unknown timezone `%s`, use an IANA name like `Asia/Ho_Chi_Minh`", timezone)
+	}
+
+	if err := s.chats.SetTimezone(ctx, chatID, loc.String()); err != nil {
+		if errors.Is(err, repository.ErrChatNotRegistered) {
+			return "Register the chat with /register first"
+		}
+		slog.Error("setting timezone", "chatID", chatID, "timezone", timezone, logger.Err(err))
+		return "Failed to set timezone"
+	}
+
+	return fmt.Sprintf("Timezone set to `%s`, local time is %s", loc, time.Now().In(loc).Format("15:04"))
+}
+
+func (s *settings) setTime(ctx context.Context, chatID int64, topicArg, timesArg string) string {
+	topic, err := domain.ParseTopic(topicArg)
+	if err != nil {
+		return fmt.Sprintf("%v. Available topics: %s", err, joinTopics(domain.Topics()))
+	}
+
+	times, err := domain.ParseDeliveryTimes(timesArg)
+	if err != nil {
+		return err.Error()
+	}
+
+	if err := s.subscriptions.SetDeliveryTimes(ctx, chatID, topic, times); err != nil {
+		if errors.Is(err, repository.ErrSubscriptionNotFound) {
+			return fmt.Sprintf("Subscribe to %s first with /subscribe %s", topic, topic)
+		}
+		slog.Error("setting delivery times", "chatID", chatID, "topic", topic, logger.Err(err))
+		return "Failed to set delivery time"
+	}
+
+	return fmt.Sprintf("%s will be delivered at %s", topic, timesArg)
+}
+
+func (s *settings) setWeatherForecast(ctx context.Context, chatID int64, enabled bool) string {
+	if err := s.chats.SetWeatherForecast(ctx, chatID, enabled); err != nil {
+		if errors.Is(err, repository.ErrChatNotRegistered) {
+			return "Register the chat with /register first"
+		}
+		slog.Error("setting weather forecast", "chatID", chatID, "enabled", enabled, logger.Err(err))
+		return "Failed to set weather forecast"
+	}
+
+	if enabled {
+		return "The morning weather report will show the forecast for the day"
+	}
+	return "The morning weather report will show the current weather"
+}
@@ -0,0 +1,157 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"github.com/sushkevichd/day-guide-telegram-bot/pkg/domain"
+	"github.com/sushkevichd/day-guide-telegram-bot/pkg/logger"
+	"github.com/sushkevichd/day-guide-telegram-bot/pkg/repository"
+	"github.com/sushkevichd/day-guide-telegram-bot/pkg/telegram"
+)
+
+type SubscriptionManager interface {
+	Subscribe(ctx context.Context, chatID int64, topic domain.Topic) error
+	Unsubscribe(ctx context.Context, chatID int64, topic domain.Topic) error
+	FetchTopics(ctx context.Context, chatID int64) ([]domain.Topic, error)
+}
+
+type subscribe struct {
+	manager SubscriptionManager
+	outCh   chan<- domain.Message
+}
+
+func NewSubscribe(
+	manager SubscriptionManager,
+	outCh chan<- domain.Message,
+) *subscribe {
+	return &subscribe{
+		manager: manager,
+		outCh:   outCh,
+	}
+}
+
+func (s *subscribe) Spec() telegram.CommandSpec {
+	return telegram.CommandSpec{
+		Name:        "subscribe",
+		Description: "Subscribe the chat to a daily report topic",
+	}
+}
+
+func (s *subscribe) Execute(update *tgbotapi.Update, args []string) {
+	ctx := context.TODO()
+	chatID := update.Message.Chat.ID
+
+	if len(args) == 0 {
+		s.reply(update, listSubscriptions(ctx, s.manager, chatID))
+		return
+	}
+
+	topic, err := domain.ParseTopic(args[0])
+	if err != nil {
+		s.reply(update, fmt.Sprintf("%v. Available topics: %s", err, joinTopics(domain.Topics())))
+		return
+	}
+
+	msg := fmt.Sprintf("Subscribed to %s", topic)
+	if err := s.manager.Subscribe(ctx, chatID, topic); err != nil {
+		slog.Error("subscribing chat", "chatID", chatID, "topic", topic, logger.Err(err))
+
+		if errors.Is(err, repository.ErrChatNotRegistered) {
+			msg = "Register the chat with /register first"
+		} else {
+			msg = "Subscription failed"
+		}
+	}
+
+	s.reply(update, msg)
+}
+
+func (s *subscribe) reply(update *tgbotapi.Update, content string) {
+	s.outCh <- &domain.TextMessage{
+		ChatID:           update.Message.Chat.ID,
+		ReplyToMessageID: update.Message.MessageID,
+		Content:          content,
+	}
+}
+
+type unsubscribe struct {
+	manager SubscriptionManager
+	outCh   chan<- domain.Message
+}
+
+func NewUnsubscribe(
+	manager SubscriptionManager,
+	outCh chan<- domain.Message,
+) *unsubscribe {
+	return &unsubscribe{
+		manager: manager,
+		outCh:   outCh,
+	}
+}
+
+func (u *unsubscribe) Spec() telegram.CommandSpec {
+	return telegram.CommandSpec{
+		Name:        "unsubscribe",
+		Description: "Unsubscribe the chat from a daily report topic",
+	}
+}
+
+func (u *unsubscribe) Execute(update *tgbotapi.Update, args []string) {
+	ctx := context.TODO()
+	chatID := update.Message.Chat.ID
+
+	if len(args) == 0 {
+		u.reply(update, listSubscriptions(ctx, u.manager, chatID))
+		return
+	}
+
+	topic, err := domain.ParseTopic(args[0])
+	if err != nil {
+		u.reply(update, fmt.Sprintf("%v. Available topics: %s", err, joinTopics(domain.Topics())))
+		return
+	}
+
+	msg := fmt.Sprintf("Unsubscribed from %s", topic)
+	if err := u.manager.Unsubscribe(ctx, chatID, topic); err != nil {
+		slog.Error("unsubscribing chat", "chatID", chatID, "topic", topic, logger.Err(err))
+		msg = "Unsubscription failed"
+	}
+
+	u.reply(update, msg)
+}
+
+func (u *unsubscribe) reply(update *tgbotapi.Update, content string) {
+	u.outCh <- &domain.TextMessage{
+		ChatID:           update.Message.Chat.ID,
+		ReplyToMessageID: update.Message.MessageID,
+		Content:          content,
+	}
+}
+
+func listSubscriptions(ctx context.Context, manager SubscriptionManager, chatID int64) string {
+	topics, err := manager.FetchTopics(ctx, chatID)
+	if err != nil {
+		slog.Error("fetching subscriptions", "chatID", chatID, logger.Err(err))
+		return "Failed to fetch subscriptions"
+	}
+
+	if len(topics) == 0 {
+		return fmt.Sprintf("No subscriptions yet. Available topics: %s", joinTopics(domain.Topics()))
+	}
+
+	return fmt.Sprintf("Subscribed to: %s\nAvailable topics: %s", joinTopics(topics), joinTopics(domain.Topics()))
+}
+
+func joinTopics(topics []domain.Topic) string {
+	names := make([]string, 0, len(topics))
+	for _, t := range topics {
+		names = append(names, t.String())
+	}
+	return strings.Join(names, ", ")
+}
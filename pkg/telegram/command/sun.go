@@ -0,0 +1,51 @@
+package command
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"github.com/sushkevichd/day-guide-telegram-bot/pkg/domain"
+	"github.com/sushkevichd/day-guide-telegram-bot/pkg/telegram"
+)
+
+type SunReportGenerator interface {
+	Generate(ctx context.Context, chatID int64, city string) (string, error)
+}
+
+type sun struct {
+	reportGenerator SunReportGenerator
+	outCh           chan<- domain.Message
+}
+
+func NewSun(
+	reportGenerator SunReportGenerator,
+	outCh chan<- domain.Message,
+) *sun {
+	return &sun{
+		reportGenerator: reportGenerator,
+		outCh:           outCh,
+	}
+}
+
+func (s *sun) Spec() telegram.CommandSpec {
+	return telegram.CommandSpec{
+		Name:        "sun",
+		Description: "Sunrise, sunset and day length in the chat cities",
+	}
+}
+
+func (s *sun) Execute(update *tgbotapi.Update, args []string) {
+	response, err := s.reportGenerator.Generate(context.TODO(), update.Message.Chat.ID, strings.Join(args, " "))
+	if err != nil {
+		response = fmt.Sprintf("Failed to generate sun report: %v", err)
+	}
+
+	s.outCh <- &domain.TextMessage{
+		ChatID:           update.Message.Chat.ID,
+		ReplyToMessageID: update.Message.MessageID,
+		Content:          response,
+	}
+}
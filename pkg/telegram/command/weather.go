@@ -2,46 +2,231 @@ package command
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log/slog"
+	"strconv"
 	"strings"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 
 	"github.com/sushkevichd/day-guide-telegram-bot/pkg/domain"
+	"github.com/sushkevichd/day-guide-telegram-bot/pkg/formatter"
+	"github.com/sushkevichd/day-guide-telegram-bot/pkg/logger"
+	"github.com/sushkevichd/day-guide-telegram-bot/pkg/repository"
+	"github.com/sushkevichd/day-guide-telegram-bot/pkg/telegram"
+)
+
+const (
+	weatherCommand         = "weather"
+	weatherCallbackAll     = "all"
+	weatherCallbackCity    = "city"
+	weatherPickerAllLabel  = "Все города"
+	weatherKeyboardRowSize = 3
 )
 
 type WeatherReportGenerator interface {
-	Generate(ctx context.Context) (string, error)
+	Generate(ctx context.Context, chatID int64) (string, error)
+	GenerateForLocation(ctx context.Context, chatID int64, locationID int64) (string, error)
+}
+
+type LocationManager interface {
+	Add(ctx context.Context, chatID int64, l domain.Location) error
+	Remove(ctx context.Context, chatID int64, name string) error
+	FetchByChatID(ctx context.Context, chatID int64) ([]domain.Location, error)
+}
+
+type Geocoder interface {
+	Geocode(ctx context.Context, query string) (*domain.Location, error)
 }
 
 type weather struct {
 	reportGenerator WeatherReportGenerator
+	locations       LocationManager
+	geocoder        Geocoder
 	outCh           chan<- domain.Message
 }
 
 func NewWeather(
 	reportGenerator WeatherReportGenerator,
+	locations LocationManager,
+	geocoder Geocoder,
 	outCh chan<- domain.Message,
 ) *weather {
 	return &weather{
 		reportGenerator: reportGenerator,
+		locations:       locations,
+		geocoder:        geocoder,
 		outCh:           outCh,
 	}
 }
 
-func (w *weather) CanExecute(update *tgbotapi.Update) bool {
-	return update.Message != nil && strings.HasPrefix(update.Message.Text, "/weather")
+func (w *weather) Spec() telegram.CommandSpec {
+	return telegram.CommandSpec{
+		Name:        weatherCommand,
+		Aliases:     []string{"w"},
+		Description: "Weather in the chat cities, add or remove cities",
+	}
 }
 
-func (w *weather) Execute(update *tgbotapi.Update) {
-	response, err := w.reportGenerator.Generate(context.TODO())
-	if err != nil {
-		response = fmt.Sprintf("Failed to generate weather report: %v", err)
+func (w *weather) Execute(update *tgbotapi.Update, args []string) {
+	ctx := context.TODO()
+	chatID := update.Message.Chat.ID
+
+	var response string
+	var keyboard *tgbotapi.InlineKeyboardMarkup
+	switch {
+	case len(args) == 0:
+		response = w.report(ctx, chatID)
+		keyboard = w.cityKeyboard(ctx, chatID)
+	case args[0] == "add" && len(args) > 1:
+		response = w.add(ctx, chatID, strings.Join(args[1:], " "))
+	case args[0] == "remove" && len(args) > 1:
+		response = w.remove(ctx, chatID, strings.Join(args[1:], " "))
+	case args[0] == "list":
+		response = w.list(ctx, chatID)
+	default:
+		response = "Usage: /weather, /weather add <city>, /weather remove <city>, /weather list"
 	}
 
 	w.outCh <- &domain.TextMessage{
-		ChatID:           update.Message.Chat.ID,
+		ChatID:           chatID,
 		ReplyToMessageID: update.Message.MessageID,
 		Content:          response,
+		ReplyMarkup:      keyboard,
+	}
+}
+
+// HandleCallback shows the weather of the city picked with the inline keyboard
+func (w *weather) HandleCallback(query *tgbotapi.CallbackQuery, args []string) {
+	ctx := context.TODO()
+	chatID := query.Message.Chat.ID
+
+	var response string
+	var err error
+	switch {
+	case len(args) == 2 && args[0] == weatherCallbackCity:
+		var locationID int64
+		if locationID, err = strconv.ParseInt(args[1], 10, 64); err == nil {
+			response, err = w.reportGenerator.GenerateForLocation(ctx, chatID, locationID)
+		}
+	default:
+		response, err = w.reportGenerator.Generate(ctx, chatID)
+	}
+	if err != nil {
+		slog.Error("generating weather report", "chatID", chatID, "data", query.Data, logger.Err(err))
+		return
+	}
+
+	w.outCh <- &domain.EditTextMessage{
+		ChatID:      chatID,
+		MessageID:   query.Message.MessageID,
+		Content:     response,
+		ReplyMarkup: w.cityKeyboard(ctx, chatID),
+	}
+}
+
+func (w *weather) report(ctx context.Context, chatID int64) string {
+	response, err := w.reportGenerator.Generate(ctx, chatID)
+	if err != nil {
+		return fmt.Sprintf("Failed to generate weather report: %v", err)
+	}
+	return response
+}
+
+func (w *weather) cityKeyboard(ctx context.Context, chatID int64) *tgbotapi.InlineKeyboardMarkup {
+	locations, err := w.locations.FetchByChatID(ctx, chatID)
+	if err != nil {
+		slog.Error("fetching locations", "chatID", chatID, logger.Err(err))
+		return nil
+	}
+
+	// A picker makes sense only when there is something to pick from
+	if len(locations) < 2 {
+		return nil
+	}
+
+	var rows [][]tgbotapi.InlineKeyboardButton
+	var row []tgbotapi.InlineKeyboardButton
+	for _, l := range locations {
+		data, _ := telegram.CallbackData(weatherCommand, weatherCallbackCity, strconv.FormatInt(l.ID, 10))
+		row = append(row, tgbotapi.NewInlineKeyboardButtonData(l.Name, data))
+		if len(row) == weatherKeyboardRowSize {
+			rows = append(rows, row)
+			row = nil
+		}
+	}
+	if len(row) > 0 {
+		rows = append(rows, row)
+	}
+
+	allData, _ := telegram.CallbackData(weatherCommand, weatherCallbackAll)
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(tgbotapi.NewInlineKeyboardButtonData(weatherPickerAllLabel, allData)))
+
+	keyboard := tgbotapi.NewInlineKeyboardMarkup(rows...)
+	return &keyboard
+}
+
+func (w *weather) add(ctx context.Context, chatID int64, city string) string {
+	location, err := w.geocoder.Geocode(ctx, city)
+	if err != nil {
+		slog.Error("geocoding city", "city", city, logger.Err(err))
+		return fmt.Sprintf("Failed to find city %s", formatter.StripMarkdown(city))
+	}
+
+	if err := w.locations.Add(ctx, chatID, *location); err != nil {
+		slog.Error("adding location", "chatID", chatID, "location", location, logger.Err(err))
+
+		switch {
+		case errors.Is(err, repository.ErrLocationAlreadyExists):
+			return fmt.Sprintf("%s is already in the list", location.Name)
+		case errors.Is(err, repository.ErrChatNotRegistered):
+			return "Register the chat with /register first"
+		default:
+			return "Failed to add city"
+		}
+	}
+
+	return fmt.Sprintf("Added %s, the weather will be available after the next update", location.Name)
+}
+
+func (w *weather) remove(ctx context.Context, chatID int64, city string) string {
+	name := city
+
+	err := w.locations.Remove(ctx, chatID, name)
+	if errors.Is(err, repository.ErrLocationNotFound) {
+		// The city may be stored under its canonical name, e.g. "Санкт-Петербург" for "Saint Petersburg"
+		if location, geoErr := w.geocoder.Geocode(ctx, city); geoErr == nil {
+			name = location.Name
+			err = w.locations.Remove(ctx, chatID, name)
+		}
+	}
+
+	if err != nil {
+		if errors.Is(err, repository.ErrLocationNotFound) {
+			return fmt.Sprintf("%s is not in the list", formatter.StripMarkdown(city))
+		}
+		slog.Error("removing location", "chatID", chatID, "city", city, logger.Err(err))
+		return "Failed to remove city"
+	}
+
+	return fmt.Sprintf("Removed %s", name)
+}
+
+func (w *weather) list(ctx context.Context, chatID int64) string {
+	locations, err := w.locations.FetchByChatID(ctx, chatID)
+	if err != nil {
+		slog.Error("fetching locations", "chatID", chatID, logger.Err(err))
+		return "Failed to fetch cities"
+	}
+
+	if len(locations) == 0 {
+		return "No cities yet, add one with /weather add <city>"
+	}
+
+	names := make([]string, 0, len(locations))
+	for _, l := range locations {
+		names = append(names, l.Name)
 	}
+	return "Cities: " + strings.Join(names, ", ")
 }
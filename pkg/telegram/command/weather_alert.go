@@ -0,0 +1,145 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"github.com/sushkevichd/day-guide-telegram-bot/pkg/domain"
+	"github.com/sushkevichd/day-guide-telegram-bot/pkg/logger"
+	"github.com/sushkevichd/day-guide-telegram-bot/pkg/repository"
+	"github.com/sushkevichd/day-guide-telegram-bot/pkg/telegram"
+)
+
+const weatherAlertUsage = "Usage: /weatheralert temp <°C drop>, /weatheralert wind <m/s>, /weatheralert storm on, " +
+	"/weatheralert pressure <mm change>, /weatheralert <alert> off"
+
+type WeatherAlertManager interface {
+	SetRule(ctx context.Context, r domain.WeatherAlertRule) error
+	RemoveRule(ctx context.Context, chatID int64, kind domain.WeatherAlertKind) error
+	FetchRulesByChatID(ctx context.Context, chatID int64) ([]domain.WeatherAlertRule, error)
+}
+
+type weatherAlert struct {
+	manager WeatherAlertManager
+	outCh   chan<- domain.Message
+}
+
+func NewWeatherAlert(
+	manager WeatherAlertManager,
+	outCh chan<- domain.Message,
+) *weatherAlert {
+	return &weatherAlert{
+		manager: manager,
+		outCh:   outCh,
+	}
+}
+
+func (w *weatherAlert) Spec() telegram.CommandSpec {
+	return telegram.CommandSpec{
+		Name:        "weatheralert",
+		Description: "Severe weather alerts for the chat cities",
+	}
+}
+
+func (w *weatherAlert) Execute(update *tgbotapi.Update, args []string) {
+	ctx := context.TODO()
+	chatID := update.Message.Chat.ID
+
+	var response string
+	switch len(args) {
+	case 0:
+		response = w.list(ctx, chatID)
+	case 2:
+		response = w.set(ctx, chatID, args[0], args[1])
+	default:
+		response = weatherAlertUsage
+	}
+
+	w.outCh <- &domain.TextMessage{
+		ChatID:           chatID,
+		ReplyToMessageID: update.Message.MessageID,
+		Content:          response,
+	}
+}
+
+func (w *weatherAlert) set(ctx context.Context, chatID int64, kindArg, valueArg string) string {
+	kind, err := domain.ParseWeatherAlertKind(kindArg)
+	if err != nil {
+		return fmt.Sprintf("%v. %s", err, weatherAlertUsage)
+	}
+
+	if strings.EqualFold(valueArg, "off") {
+		if err := w.manager.RemoveRule(ctx, chatID, kind); err != nil {
+			if errors.Is(err, repository.ErrWeatherAlertNotFound) {
+				return fmt.Sprintf("Weather alert %s is not set", kind)
+			}
+			slog.Error("removing weather alert", "chatID", chatID, "kind", kind, logger.Err(err))
+			return "Failed to remove weather alert"
+		}
+		return fmt.Sprintf("Weather alert %s removed", kind)
+	}
+
+	rule := domain.WeatherAlertRule{ChatID: chatID, Kind: kind}
+	if kind.HasThreshold() {
+		threshold, err := strconv.ParseFloat(strings.Replace(valueArg, ",", ".", 1), 64)
+		if err != nil || threshold <= 0 {
+			return fmt.Sprintf("Invalid threshold %q. %s", valueArg, weatherAlertUsage)
+		}
+		rule.Threshold = threshold
+	} else if !strings.EqualFold(valueArg, "on") {
+		return weatherAlertUsage
+	}
+
+	if err := w.manager.SetRule(ctx, rule); err != nil {
+		if errors.Is(err, repository.ErrChatNotRegistered) {
+			return "Register the chat with /register first"
+		}
+		slog.Error("setting weather alert", "chatID", chatID, "kind", kind, logger.Err(err))
+		return "Failed to set weather alert"
+	}
+
+	return fmt.Sprintf("Weather alert set: %s", formatWeatherAlertRule(rule))
+}
+
+func (w *weatherAlert) list(ctx context.Context, chatID int64) string {
+	rules, err := w.manager.FetchRulesByChatID(ctx, chatID)
+	if err != nil {
+		slog.Error("fetching weather alerts", "chatID", chatID, logger.Err(err))
+		return "Failed to fetch weather alerts"
+	}
+
+	if len(rules) == 0 {
+		return "No weather alerts yet. " + weatherAlertUsage
+	}
+
+	var sb strings.Builder
+	sb.WriteString("Weather alerts:\n")
+	for _, r := range rules {
+		sb.WriteString(formatWeatherAlertRule(r))
+		sb.WriteString("\n")
+	}
+	sb.WriteString(weatherAlertUsage)
+
+	return sb.String()
+}
+
+func formatWeatherAlertRule(r domain.WeatherAlertRule) string {
+	switch r.Kind {
+	case domain.WeatherAlertTempDrop:
+		return fmt.Sprintf("temp - temperature drops by more than %g°C versus yesterday", r.Threshold)
+	case domain.WeatherAlertWind:
+		return fmt.Sprintf("wind - wind is stronger than %gm/s", r.Threshold)
+	case domain.WeatherAlertStorm:
+		return "storm - thunderstorm or snow"
+	case domain.WeatherAlertPressure:
+		return fmt.Sprintf("pressure - pressure changes by more than %gmm versus yesterday", r.Threshold)
+	default:
+		return r.Kind.String()
+	}
+}
@@ -0,0 +1,103 @@
+package command
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"github.com/sushkevichd/day-guide-telegram-bot/pkg/domain"
+	"github.com/sushkevichd/day-guide-telegram-bot/pkg/formatter"
+	"github.com/sushkevichd/day-guide-telegram-bot/pkg/logger"
+	"github.com/sushkevichd/day-guide-telegram-bot/pkg/report"
+	"github.com/sushkevichd/day-guide-telegram-bot/pkg/telegram"
+)
+
+const (
+	defaultWeatherChartDays = 7
+	maxWeatherChartDays     = 90
+
+	weatherChartUsage = "Usage: /weatherchart <city> [days]"
+)
+
+type WeatherChartGenerator interface {
+	Generate(ctx context.Context, chatID int64, city string, days int) ([]byte, string, error)
+}
+
+type weatherChart struct {
+	reportGenerator WeatherChartGenerator
+	outCh           chan<- domain.Message
+}
+
+func NewWeatherChart(
+	reportGenerator WeatherChartGenerator,
+	outCh chan<- domain.Message,
+) *weatherChart {
+	return &weatherChart{
+		reportGenerator: reportGenerator,
+		outCh:           outCh,
+	}
+}
+
+func (w *weatherChart) Spec() telegram.CommandSpec {
+	return telegram.CommandSpec{
+		Name:        "weatherchart",
+		Description: "Temperature and pressure chart of a chat city, e.g. /weatherchart Анталья 14",
+	}
+}
+
+func (w *weatherChart) Execute(update *tgbotapi.Update, args []string) {
+	days := defaultWeatherChartDays
+	if len(args) > 1 {
+		if n, err := strconv.Atoi(args[len(args)-1]); err == nil {
+			days = n
+			args = args[:len(args)-1]
+		}
+	}
+
+	if len(args) == 0 {
+		w.reply(update, weatherChartUsage)
+		return
+	}
+	if days < 1 || days > maxWeatherChartDays {
+		w.reply(update, fmt.Sprintf("Chart is available for 1 to %d days", maxWeatherChartDays))
+		return
+	}
+
+	city := strings.Join(args, " ")
+	imageBytes, caption, err := w.reportGenerator.Generate(context.TODO(), update.Message.Chat.ID, city, days)
+	if err != nil {
+		// The city is echoed in the reply
+		city = formatter.StripMarkdown(city)
+
+		switch {
+		case errors.Is(err, report.ErrLocationNotAdded):
+			w.reply(update, fmt.Sprintf("%s is not in the list, add the city with /weather add %s", city, city))
+		case errors.Is(err, report.ErrNotEnoughWeatherHistory):
+			w.reply(update, fmt.Sprintf("No weather history for %s yet, it is collected with every weather update", city))
+		default:
+			slog.Error("generating weather chart", "city", city, "days", days, logger.Err(err))
+			w.reply(update, fmt.Sprintf("``` Failed to generate weather chart for %s: %v ```", city, err))
+		}
+		return
+	}
+
+	w.outCh <- &domain.ImageMessage{
+		ChatID:           update.Message.Chat.ID,
+		ReplyToMessageID: update.Message.MessageID,
+		Content:          imageBytes,
+		Caption:          caption,
+	}
+}
+
+func (w *weatherChart) reply(update *tgbotapi.Update, content string) {
+	w.outCh <- &domain.TextMessage{
+		ChatID:           update.Message.Chat.ID,
+		ReplyToMessageID: update.Message.MessageID,
+		Content:          content,
+	}
+}
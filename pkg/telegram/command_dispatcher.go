@@ -1,28 +1,211 @@
 package telegram
 
 import (
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"github.com/sushkevichd/day-guide-telegram-bot/pkg/domain"
+)
+
+const (
+	helpCommand  = "help"
+	startCommand = "start" // sent by Telegram clients when a user opens the bot
+
+	callbackDataSeparator = ":"
+	maxCallbackDataLength = 64 // bytes, limited by Telegram
 )
 
+// CommandSpec describes how a command is invoked and listed in /help and the Telegram menu
+type CommandSpec struct {
+	Name        string
+	Aliases     []string
+	Description string
+}
+
 type Command interface {
-	CanExecute(update *tgbotapi.Update) bool
-	Execute(update *tgbotapi.Update)
+	Spec() CommandSpec
+	Execute(update *tgbotapi.Update, args []string)
+}
+
+// CallbackHandler is implemented by commands attaching inline keyboards built with CallbackData
+type CallbackHandler interface {
+	HandleCallback(query *tgbotapi.CallbackQuery, args []string)
+}
+
+// TextHandler is implemented by commands which also understand plain messages, e.g. "150 usd try"
+type TextHandler interface {
+	// HandleText reports whether the message was recognized and answered
+	HandleText(update *tgbotapi.Update) bool
 }
 
 type commandDispatcher struct {
+	botName  string
 	commands []Command
+	routes   map[string]Command
+	outCh    chan<- domain.Message
 }
 
-func NewCommandDispatcher(commands []Command) *commandDispatcher {
+func NewCommandDispatcher(botName string, commands []Command, outCh chan<- domain.Message) (*commandDispatcher, error) {
+	routes := make(map[string]Command)
+	for _, command := range commands {
+		spec := command.Spec()
+		for _, name := range append([]string{spec.Name}, spec.Aliases...) {
+			name = strings.ToLower(name)
+			if name == helpCommand || name == startCommand {
+				return nil, fmt.Errorf("command name %q is reserved", name)
+			}
+			if _, ok := routes[name]; ok {
+				return nil, fmt.Errorf("duplicate command name %q", name)
+			}
+			routes[name] = command
+		}
+	}
+
 	return &commandDispatcher{
+		botName:  botName,
 		commands: commands,
-	}
+		routes:   routes,
+		outCh:    outCh,
+	}, nil
 }
 
 func (d *commandDispatcher) ExecuteCommands(update tgbotapi.Update) {
+	if update.Message == nil {
+		return
+	}
+
+	name, mention, args, ok := ParseCommand(update.Message.Text)
+	if !ok {
+		d.handleText(&update)
+		return
+	}
+
+	// In groups a command may be addressed to another bot, e.g. /rate@OtherBot
+	if mention != "" && !strings.EqualFold(mention, d.botName) {
+		return
+	}
+
+	if name == helpCommand || name == startCommand {
+		d.help(&update)
+		return
+	}
+
+	command, ok := d.routes[name]
+	if !ok {
+		slog.Debug("unknown command", "command", name, "chat", update.Message.Chat.ID)
+		return
+	}
+
+	command.Execute(&update, args)
+}
+
+// ExecuteCallback routes a pressed inline button to the command which created it
+func (d *commandDispatcher) ExecuteCallback(query tgbotapi.CallbackQuery) {
+	// Telegram shows a loading indicator on the button until the query is answered
+	defer func() {
+		d.outCh <- &domain.CallbackAnswer{CallbackQueryID: query.ID}
+	}()
+
+	name, args := ParseCallbackData(query.Data)
+
+	command, ok := d.routes[name]
+	if !ok {
+		slog.Warn("callback for unknown command", "command", name, "data", query.Data)
+		return
+	}
+
+	handler, ok := command.(CallbackHandler)
+	if !ok || query.Message == nil {
+		slog.Warn("callback is not supported", "command", name, "data", query.Data)
+		return
+	}
+
+	handler.HandleCallback(&query, args)
+}
+
+// handleText offers a plain message to the text handlers in the order the commands were registered
+func (d *commandDispatcher) handleText(update *tgbotapi.Update) {
+	if strings.TrimSpace(update.Message.Text) == "" {
+		return
+	}
+
+	for _, command := range d.commands {
+		if handler, ok := command.(TextHandler); ok && handler.HandleText(update) {
+			return
+		}
+	}
+}
+
+// BotCommands returns the command list to publish with setMyCommands
+func (d *commandDispatcher) BotCommands() []tgbotapi.BotCommand {
+	botCommands := make([]tgbotapi.BotCommand, 0, len(d.commands)+1)
+	for _, command := range d.commands {
+		spec := command.Spec()
+		botCommands = append(botCommands, tgbotapi.BotCommand{
+			Command:     spec.Name,
+			Description: spec.Description,
+		})
+	}
+	botCommands = append(botCommands, tgbotapi.BotCommand{
+		Command:     helpCommand,
+		Description: "Show available commands",
+	})
+	return botCommands
+}
+
+func (d *commandDispatcher) help(update *tgbotapi.Update) {
+	specs := make([]CommandSpec, 0, len(d.commands))
 	for _, command := range d.commands {
-		if command.CanExecute(&update) {
-			command.Execute(&update)
+		specs = append(specs, command.Spec())
+	}
+	sort.Slice(specs, func(i, j int) bool { return specs[i].Name < specs[j].Name })
+
+	var sb strings.Builder
+	sb.WriteString("Available commands:\n")
+	for _, spec := range specs {
+		sb.WriteString(fmt.Sprintf("/%s", spec.Name))
+		for _, alias := range spec.Aliases {
+			sb.WriteString(fmt.Sprintf(", /%s", alias))
 		}
+		sb.WriteString(fmt.Sprintf(" - %s\n", spec.Description))
+	}
+
+	d.outCh <- &domain.TextMessage{
+		ChatID:           update.Message.Chat.ID,
+		ReplyToMessageID: update.Message.MessageID,
+		Content:          sb.String(),
+	}
+}
+
+// ParseCommand splits "/cmd@botname arg1 arg2" into the lowercase command name, the mentioned bot and the arguments.
+// ok is false when the text is not a command.
+func ParseCommand(text string) (name, mention string, args []string, ok bool) {
+	fields := strings.Fields(text)
+	if len(fields) == 0 || !strings.HasPrefix(fields[0], "/") || len(fields[0]) < 2 {
+		return "", "", nil, false
 	}
+
+	name, mention, _ = strings.Cut(fields[0][1:], "@")
+	if name == "" {
+		return "", "", nil, false
+	}
+
+	return strings.ToLower(name), mention, fields[1:], true
+}
+
+// CallbackData encodes the command name and the arguments into inline button data.
+// ok is false when the result exceeds the Telegram limit.
+func CallbackData(command string, args ...string) (data string, ok bool) {
+	data = strings.Join(append([]string{command}, args...), callbackDataSeparator)
+	return data, len(data) <= maxCallbackDataLength
+}
+
+// ParseCallbackData splits inline button data created by CallbackData
+func ParseCallbackData(data string) (command string, args []string) {
+	parts := strings.Split(data, callbackDataSeparator)
+	return strings.ToLower(parts[0]), parts[1:]
 }
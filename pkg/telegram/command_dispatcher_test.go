@@ -0,0 +1,129 @@
+package telegram
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"github.com/sushkevichd/day-guide-telegram-bot/pkg/domain"
+)
+
+func TestParseCommand(t *testing.T) {
+	tests := []struct {
+		text        string
+		wantName    string
+		wantMention string
+		wantArgs    []string
+		wantOK      bool
+	}{
+		{"/rate", "rate", "", []string{}, true},
+		{"/Rate USD", "rate", "", []string{"USD"}, true},
+		{"/RATE@DayGuideBot usd  try", "rate", "DayGuideBot", []string{"usd", "try"}, true},
+		{"/rate@OtherBot", "rate", "OtherBot", []string{}, true},
+		{"  /weather   add Moscow ", "weather", "", []string{"add", "Moscow"}, true},
+		{"/", "", "", nil, false},
+		{"/@DayGuideBot", "", "", nil, false},
+		{"rate USD", "", "", nil, false},
+		{"150 usd try", "", "", nil, false},
+		{"", "", "", nil, false},
+	}
+
+	for _, tt := range tests {
+		name, mention, args, ok := ParseCommand(tt.text)
+		if ok != tt.wantOK || name != tt.wantName || mention != tt.wantMention || !reflect.DeepEqual(args, tt.wantArgs) {
+			t.Errorf("ParseCommand(%q) = %q, %q, %q, %v, want %q, %q, %q, %v",
+				tt.text, name, mention, args, ok, tt.wantName, tt.wantMention, tt.wantArgs, tt.wantOK)
+		}
+	}
+}
+
+func TestCallbackData(t *testing.T) {
+	tests := []struct {
+		name     string
+		command  string
+		args     []string
+		wantData string
+		wantOK   bool
+	}{
+		{"no args", "holiday", nil, "holiday", true},
+		{"args", "weather", []string{"city", "Moscow"}, "weather:city:Moscow", true},
+		{"64 bytes", "weather", []string{strings.Repeat("a", 56)}, "weather:" + strings.Repeat("a", 56), true},
+		{"65 bytes", "weather", []string{strings.Repeat("a", 57)}, "weather:" + strings.Repeat("a", 57), false},
+		// Cyrillic letters take 2 bytes, 28 of them fit in 64 bytes with the command but not 29
+		{"multibyte within the limit", "weather", []string{strings.Repeat("я", 28)}, "weather:" + strings.Repeat("я", 28), true},
+		{"multibyte over the limit", "weather", []string{strings.Repeat("я", 29)}, "weather:" + strings.Repeat("я", 29), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, ok := CallbackData(tt.command, tt.args...)
+			if data != tt.wantData || ok != tt.wantOK {
+				t.Errorf("CallbackData(%q, %q) = %q, %v, want %q, %v", tt.command, tt.args, data, ok, tt.wantData, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestParseCallbackData(t *testing.T) {
+	data, _ := CallbackData("Weather", "city", "Moscow")
+
+	command, args := ParseCallbackData(data)
+	if command != "weather" || !reflect.DeepEqual(args, []string{"city", "Moscow"}) {
+		t.Errorf("ParseCallbackData(%q) = %q, %q, want \"weather\", [\"city\" \"Moscow\"]", data, command, args)
+	}
+
+	command, args = ParseCallbackData("holiday")
+	if command != "holiday" || len(args) != 0 {
+		t.Errorf("ParseCallbackData(\"holiday\") = %q, %q, want \"holiday\" without args", command, args)
+	}
+}
+
+type recordingCommand struct {
+	spec CommandSpec
+	args [][]string
+}
+
+func (c *recordingCommand) Spec() CommandSpec {
+	return c.spec
+}
+
+func (c *recordingCommand) Execute(_ *tgbotapi.Update, args []string) {
+	c.args = append(c.args, args)
+}
+
+func TestExecuteCommands(t *testing.T) {
+	tests := []struct {
+		text     string
+		wantArgs [][]string
+	}{
+		{"/rate usd", [][]string{{"usd"}}},
+		{"/RATES", [][]string{{}}},
+		{"/rate@dayguidebot usd", [][]string{{"usd"}}},
+		{"/rate@OtherBot usd", nil},
+		{"/unknown", nil},
+		{"rate usd", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			command := &recordingCommand{spec: CommandSpec{Name: "rate", Aliases: []string{"rates"}}}
+			outCh := make(chan domain.Message, 1)
+
+			d, err := NewCommandDispatcher("DayGuideBot", []Command{command}, outCh)
+			if err != nil {
+				t.Fatalf("creating dispatcher: %v", err)
+			}
+
+			d.ExecuteCommands(tgbotapi.Update{Message: &tgbotapi.Message{Text: tt.text, Chat: &tgbotapi.Chat{ID: 1}}})
+
+			if !reflect.DeepEqual(command.args, tt.wantArgs) {
+				t.Errorf("executed with %q, want %q", command.args, tt.wantArgs)
+			}
+			if len(outCh) != 0 {
+				t.Errorf("sent %d messages, want none", len(outCh))
+			}
+		})
+	}
+}
@@ -0,0 +1,18 @@
+package weatherprovider
+
+import (
+	"context"
+
+	"github.com/sushkevichd/day-guide-telegram-bot/pkg/domain"
+)
+
+// Names of the providers selectable by config
+const (
+	OpenWeatherMap = "openweathermap"
+	OpenMeteo      = "openmeteo"
+)
+
+// Provider fetches the current weather of a location
+type Provider interface {
+	FetchData(ctx context.Context, location domain.Location) (*domain.Weather, error)
+}
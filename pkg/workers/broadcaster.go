@@ -6,90 +6,109 @@ import (
 	"log/slog"
 	"time"
 
-	"github.com/robfig/cron/v3"
-
 	"github.com/sushkevichd/day-guide-telegram-bot/pkg/domain"
 	"github.com/sushkevichd/day-guide-telegram-bot/pkg/logger"
 )
 
-type ChatFetcher interface {
-	GetIDs(ctx context.Context) ([]int64, error)
+// ReportGenerator generates the report for the chat, now is the delivery moment in the chat's timezone
+type ReportGenerator interface {
+	Generate(ctx context.Context, chatID int64, now time.Time) (string, error)
 }
 
-type ReportGenerator interface {
-	Generate(ctx context.Context) (string, error)
+// MarkdownSender sends Markdown rendered to HTML and split into several messages when long, as the AI output may be.
+// Unlike a message put to the outgoing channel it tells whether the report reached the chat.
+type MarkdownSender interface {
+	SendMarkdown(ctx context.Context, chatID int64, text string) error
+}
+
+// DeliveryRecorder is implemented by the report generators recording what they have sent, e.g. not to repeat it in the
+// next report. The broadcaster calls it once the report of the chat is sent.
+type DeliveryRecorder interface {
+	RecordDelivery(ctx context.Context, chatID int64) error
 }
 
 type broadcaster struct {
 	name            string
-	cron            string
-	chatFetcher     ChatFetcher
+	scheduler       *Scheduler
 	reportGenerator ReportGenerator
 	outCh           chan<- domain.Message
+	sender          MarkdownSender
 }
 
 func NewBroadcaster(
-	name, cron string,
-	chatFetcher ChatFetcher,
+	name string,
+	scheduler *Scheduler,
 	reportGenerator ReportGenerator,
 	outCh chan<- domain.Message,
 ) (*broadcaster, error) {
 	return &broadcaster{
 		name:            name,
-		cron:            cron,
-		chatFetcher:     chatFetcher,
+		scheduler:       scheduler,
 		reportGenerator: reportGenerator,
 		outCh:           outCh,
 	}, nil
 }
 
+// NewMarkdownBroadcaster creates a broadcaster sending the reports with sender instead of the outgoing channel
+func NewMarkdownBroadcaster(
+	name string,
+	scheduler *Scheduler,
+	reportGenerator ReportGenerator,
+	sender MarkdownSender,
+) (*broadcaster, error) {
+	return &broadcaster{
+		name:            name,
+		scheduler:       scheduler,
+		reportGenerator: reportGenerator,
+		sender:          sender,
+	}, nil
+}
+
 func (b *broadcaster) Name() string { return b.name }
 
 func (b *broadcaster) Start(ctx context.Context) error {
-	slog.Info(fmt.Sprintf("starting %s broadcaster", b.name), "cron", b.cron)
+	slog.Info(fmt.Sprintf("starting %s broadcaster", b.name), "topic", b.scheduler.topic)
 	defer slog.Info(fmt.Sprintf("stopped %s broadcaster", b.name))
 
-	c := cron.New()
-	defer c.Stop()
-
-	job := func() {
-		if err := b.broadcast(ctx); err != nil {
-			slog.Error(fmt.Sprintf("%s pass failed", b.name), logger.Err(err))
-		}
-	}
-
-	if _, err := c.AddFunc(b.cron, job); err != nil {
-		slog.Error("failed to add cron job", "name", b.name, logger.Err(err))
-		return err
-	}
-
-	c.Start()
-	<-ctx.Done()
+	b.scheduler.Run(ctx, b.broadcast)
 
 	return nil
 }
 
-func (b *broadcaster) broadcast(ctx context.Context) error {
-	slog.Info(fmt.Sprintf("starting %s pass", b.name))
+func (b *broadcaster) broadcast(ctx context.Context, chats []DueChat) {
+	slog.Info(fmt.Sprintf("starting %s pass", b.name), "chats", len(chats))
 	startAt := time.Now()
 
-	chatIDs, err := b.chatFetcher.GetIDs(ctx)
-	if err != nil {
-		return fmt.Errorf("fetching chatIDs for broadcasting: %v", err)
-	}
+	for _, chat := range chats {
+		report, err := b.reportGenerator.Generate(ctx, chat.ID, chat.Now)
+		if err != nil {
+			slog.Error("generating report", "name", b.name, "chatID", chat.ID, logger.Err(err))
+			continue
+		}
 
-	report, err := b.reportGenerator.Generate(ctx)
-	if err != nil {
-		return fmt.Errorf("generating report: %v", err)
-	}
+		if err := b.send(ctx, chat.ID, report); err != nil {
+			slog.Error("sending report", "name", b.name, "chatID", chat.ID, logger.Err(err))
+			continue
+		}
 
-	for _, id := range chatIDs {
-		b.outCh <- &domain.TextMessage{
-			ChatID:  id,
-			Content: report,
+		if recorder, ok := b.reportGenerator.(DeliveryRecorder); ok {
+			if err := recorder.RecordDelivery(ctx, chat.ID); err != nil {
+				slog.Error("recording report delivery", "name", b.name, "chatID", chat.ID, logger.Err(err))
+			}
 		}
 	}
 
 	slog.Info(fmt.Sprintf("completed %s pass", b.name), "elapsed_time", time.Now().Sub(startAt).String())
+}
+
+func (b *broadcaster) send(ctx context.Context, chatID int64, report string) error {
+	if b.sender != nil {
+		return b.sender.SendMarkdown(ctx, chatID, report)
+	}
+
+	b.outCh <- &domain.TextMessage{
+		ChatID:  chatID,
+		Content: report,
+	}
 	return nil
 }
@@ -0,0 +1,57 @@
+package httpserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"time"
+)
+
+const shutdownTimeout = 10 * time.Second
+
+type service struct {
+	server *http.Server
+}
+
+func NewService(port string, handler http.Handler) (*service, error) {
+	return &service{
+		server: &http.Server{
+			Addr:              net.JoinHostPort("", port),
+			Handler:           handler,
+			ReadHeaderTimeout: 10 * time.Second,
+		},
+	}, nil
+}
+
+func (svc *service) Name() string { return "http server" }
+
+func (svc *service) Start(ctx context.Context) error {
+	slog.Info("starting http server", "addr", svc.server.Addr)
+	defer slog.Info("stopped http server")
+
+	errCh := make(chan error, 1)
+	go func() {
+		if err := svc.server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			errCh <- err
+		}
+		close(errCh)
+	}()
+
+	select {
+	case err := <-errCh:
+		return fmt.Errorf("serving http: %v", err)
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+
+	if err := svc.server.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("shutting down http server: %v", err)
+	}
+
+	return nil
+}
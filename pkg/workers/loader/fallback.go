@@ -0,0 +1,88 @@
+package loader
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/sushkevichd/day-guide-telegram-bot/pkg/logger"
+)
+
+type fallback[T any, P any] struct {
+	primary   FetcherOneParam[T, P]
+	secondary FetcherOneParam[T, P]
+	cooldown  time.Duration
+
+	mu             sync.Mutex
+	primaryRetryAt time.Time
+}
+
+// NewFallback creates a FetcherOneParam trying secondary when primary fails. With a cooldown primary is skipped for
+// that long after a failure, so e.g. a quota exhausted during a loader pass is not hit again for every remaining param.
+func NewFallback[T any, P any](primary, secondary FetcherOneParam[T, P], cooldown time.Duration) *fallback[T, P] {
+	return &fallback[T, P]{
+		primary:   primary,
+		secondary: secondary,
+		cooldown:  cooldown,
+	}
+}
+
+func (f *fallback[T, P]) FetchData(ctx context.Context, param P) (T, error) {
+	var primaryErr error
+	if f.primaryAvailable() {
+		data, err := f.primary.FetchData(ctx, param)
+		if err == nil {
+			return data, nil
+		}
+
+		primaryErr = err
+		f.disablePrimary()
+		slog.Warn("primary fetcher failed, falling back", "param", param, "retry_in", f.cooldown.String(), logger.Err(err))
+	}
+
+	data, err := f.secondary.FetchData(ctx, param)
+	if err != nil {
+		return data, errors.Join(primaryErr, fmt.Errorf("secondary fetcher: %v", err))
+	}
+
+	return data, nil
+}
+
+func (f *fallback[T, P]) primaryAvailable() bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return !time.Now().Before(f.primaryRetryAt)
+}
+
+func (f *fallback[T, P]) disablePrimary() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.primaryRetryAt = time.Now().Add(f.cooldown)
+}
+
+type fallbackFetcher[T any] struct {
+	fallback *fallback[T, struct{}]
+}
+
+// NewFallbackFetcher is NewFallback for fetchers without params
+func NewFallbackFetcher[T any](primary, secondary Fetcher[T], cooldown time.Duration) *fallbackFetcher[T] {
+	return &fallbackFetcher[T]{
+		fallback: NewFallback[T, struct{}](noParam[T]{primary}, noParam[T]{secondary}, cooldown),
+	}
+}
+
+func (f *fallbackFetcher[T]) FetchData(ctx context.Context) (T, error) {
+	return f.fallback.FetchData(ctx, struct{}{})
+}
+
+// noParam adapts a Fetcher to FetcherOneParam ignoring the param
+type noParam[T any] struct {
+	fetcher Fetcher[T]
+}
+
+func (n noParam[T]) FetchData(ctx context.Context, _ struct{}) (T, error) {
+	return n.fetcher.FetchData(ctx)
+}
@@ -17,34 +17,64 @@ type FetcherOneParam[T any, P any] interface {
 	FetchData(ctx context.Context, param P) (T, error)
 }
 
+type ParamsFetcher[P any] interface {
+	FetchAll(ctx context.Context) ([]P, error)
+}
+
+// StaticParams is a ParamsFetcher for a fixed list of params
+type StaticParams[P any] []P
+
+func (p StaticParams[P]) FetchAll(context.Context) ([]P, error) {
+	return p, nil
+}
+
 type Saver[T any] interface {
 	Save(ctx context.Context, data T) error
 }
 
+// Hook is run with the data of every successful save, e.g. to evaluate alerts on fresh data
+type Hook[T any] interface {
+	OnSave(ctx context.Context, data T) error
+}
+
 type service[T any, P any] struct {
-	params       []P
+	params       ParamsFetcher[P]
 	fetcher      interface{}
 	saver        Saver[T]
+	hooks        []Hook[T]
 	pollInterval time.Duration
 	name         string
 }
 
 func NewService[T any, P any](
 	name string,
-	params []P,
+	params ParamsFetcher[P],
 	fetcher interface{},
 	saver Saver[T],
 	pollInterval time.Duration,
+	hooks ...Hook[T],
 ) (*service[T, P], error) {
 	return &service[T, P]{
 		name:         name,
 		params:       params,
 		fetcher:      fetcher,
 		saver:        saver,
+		hooks:        hooks,
 		pollInterval: pollInterval,
 	}, nil
 }
 
+// NewFetcherService creates a service loading the data of a Fetcher, which takes no params
+func NewFetcherService[T any](
+	name string,
+	fetcher Fetcher[T],
+	saver Saver[T],
+	pollInterval time.Duration,
+	hooks ...Hook[T],
+) (*service[T, struct{}], error) {
+	return NewService[T, struct{}](name, nil, fetcher, saver, pollInterval, hooks...)
+}
+
 func (svc *service[T, P]) Name() string { return svc.name }
 
 func (svc *service[T, P]) Start(ctx context.Context) error {
@@ -96,11 +126,18 @@ func (svc *service[T, P]) fetchAndSave(ctx context.Context, fetcher Fetcher[T])
 	if err := svc.saver.Save(ctx, data); err != nil {
 		return fmt.Errorf("saving data: %w", err)
 	}
+
+	svc.runHooks(ctx, data)
 	return nil
 }
 
 func (svc *service[T, P]) fetchAndSaveOneParam(ctx context.Context, fetcher FetcherOneParam[T, P]) error {
-	for _, param := range svc.params {
+	params, err := svc.params.FetchAll(ctx)
+	if err != nil {
+		return fmt.Errorf("fetching params: %w", err)
+	}
+
+	for _, param := range params {
 		data, err := fetcher.FetchData(ctx, param)
 		if err != nil {
 			slog.Error("fetching data", "service", svc.name, "param", param, logger.Err(err))
@@ -113,6 +150,16 @@ func (svc *service[T, P]) fetchAndSaveOneParam(ctx context.Context, fetcher Fetc
 			slog.Error("saving data", "service", svc.name, "param", param, logger.Err(err))
 			continue
 		}
+
+		svc.runHooks(ctx, data)
 	}
 	return nil
 }
+
+func (svc *service[T, P]) runHooks(ctx context.Context, data T) {
+	for _, hook := range svc.hooks {
+		if err := hook.OnSave(ctx, data); err != nil {
+			slog.Error("running save hook", "service", svc.name, logger.Err(err))
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package workers
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/sushkevichd/day-guide-telegram-bot/pkg/domain"
+	"github.com/sushkevichd/day-guide-telegram-bot/pkg/logger"
+)
+
+const schedulerTickInterval = time.Minute
+
+type SubscriptionFetcher interface {
+	FetchSubscriptions(ctx context.Context, topic domain.Topic) ([]domain.Subscription, error)
+}
+
+// Scheduler fires a job for the chats subscribed to a topic once their local delivery time comes
+type Scheduler struct {
+	topic        domain.Topic
+	defaultTimes []domain.DeliveryTime
+	fetcher      SubscriptionFetcher
+	weekday      *time.Weekday // nil for daily delivery
+}
+
+func NewScheduler(
+	topic domain.Topic,
+	defaultTimes []domain.DeliveryTime,
+	fetcher SubscriptionFetcher,
+) *Scheduler {
+	return &Scheduler{
+		topic:        topic,
+		defaultTimes: defaultTimes,
+		fetcher:      fetcher,
+	}
+}
+
+// Weekly limits the delivery to the given weekday in the chat's timezone
+func (s *Scheduler) Weekly(day time.Weekday) *Scheduler {
+	s.weekday = &day
+	return s
+}
+
+// DueChat is a chat due for the delivery, Now is the moment of the tick in the chat's timezone, so the reports of a day
+// are generated for the chat's date rather than the server's one
+type DueChat struct {
+	ID  int64
+	Now time.Time
+}
+
+// Run blocks until ctx is done calling job with the chats due in the elapsed tick
+func (s *Scheduler) Run(ctx context.Context, job func(ctx context.Context, chats []DueChat)) {
+	ticker := time.NewTicker(schedulerTickInterval)
+	defer ticker.Stop()
+
+	last := time.Now()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			chats, err := s.dueChats(ctx, last, now)
+			if err != nil {
+				// The next tick covers the failed one, so the deliveries due in it are not lost
+				slog.Error("fetching due chats", "topic", s.topic, logger.Err(err))
+				continue
+			}
+			last = now
+			if len(chats) > 0 {
+				job(ctx, chats)
+			}
+		}
+	}
+}
+
+func (s *Scheduler) dueChats(ctx context.Context, from, to time.Time) ([]DueChat, error) {
+	subscriptions, err := s.fetcher.FetchSubscriptions(ctx, s.topic)
+	if err != nil {
+		return nil, fmt.Errorf("fetching subscriptions: %v", err)
+	}
+
+	var chats []DueChat
+	for _, sub := range subscriptions {
+		times := sub.DeliveryTimes
+		if len(times) == 0 {
+			times = s.defaultTimes
+		}
+		if isDue(times, s.weekday, sub.Location, from, to) {
+			chats = append(chats, DueChat{ID: sub.ChatID, Now: to.In(location(sub.Location))})
+		}
+	}
+	return chats, nil
+}
+
+// isDue reports whether any of the local delivery times falls into (from, to] on the weekday, if given.
+// Both local dates are checked so that a tick crossing midnight isn't missed.
+func isDue(times []domain.DeliveryTime, weekday *time.Weekday, loc *time.Location, from, to time.Time) bool {
+	loc = location(loc)
+
+	days := []time.Time{from.In(loc), to.In(loc)}
+	if days[0].YearDay() == days[1].YearDay() {
+		days = days[1:]
+	}
+
+	for _, t := range times {
+		for _, day := range days {
+			if weekday != nil && day.Weekday() != *weekday {
+				continue
+			}
+			at := t.On(day.Year(), day.Month(), day.Day(), loc)
+			if at.After(from) && !at.After(to) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func location(loc *time.Location) *time.Location {
+	if loc == nil {
+		return time.UTC
+	}
+	return loc
+}
@@ -0,0 +1,233 @@
+package workers
+
+import (
+	"context"
+	"testing"
+	"time"
+	_ "time/tzdata"
+
+	"github.com/sushkevichd/day-guide-telegram-bot/pkg/domain"
+)
+
+func TestIsDue(t *testing.T) {
+	novosibirsk := mustLoadLocation(t, "Asia/Novosibirsk") // UTC+7
+	sunday := time.Sunday
+
+	tests := []struct {
+		name    string
+		times   []domain.DeliveryTime
+		weekday *time.Weekday
+		loc     *time.Location
+		from    time.Time
+		to      time.Time
+		want    bool
+	}{
+		{
+			name:  "time in tick",
+			times: []domain.DeliveryTime{{Hour: 9}},
+			loc:   time.UTC,
+			from:  time.Date(2024, 7, 1, 8, 59, 30, 0, time.UTC),
+			to:    time.Date(2024, 7, 1, 9, 0, 30, 0, time.UTC),
+			want:  true,
+		},
+		{
+			name:  "time at tick end",
+			times: []domain.DeliveryTime{{Hour: 9}},
+			loc:   time.UTC,
+			from:  time.Date(2024, 7, 1, 8, 59, 0, 0, time.UTC),
+			to:    time.Date(2024, 7, 1, 9, 0, 0, 0, time.UTC),
+			want:  true,
+		},
+		{
+			name:  "time at tick start",
+			times: []domain.DeliveryTime{{Hour: 9}},
+			loc:   time.UTC,
+			from:  time.Date(2024, 7, 1, 9, 0, 0, 0, time.UTC),
+			to:    time.Date(2024, 7, 1, 9, 1, 0, 0, time.UTC),
+			want:  false,
+		},
+		{
+			name:  "second of several times",
+			times: []domain.DeliveryTime{{Hour: 9}, {Hour: 18}},
+			loc:   time.UTC,
+			from:  time.Date(2024, 7, 1, 17, 59, 30, 0, time.UTC),
+			to:    time.Date(2024, 7, 1, 18, 0, 30, 0, time.UTC),
+			want:  true,
+		},
+		{
+			name:  "nil location is UTC",
+			times: []domain.DeliveryTime{{Hour: 9}},
+			from:  time.Date(2024, 7, 1, 8, 59, 30, 0, time.UTC),
+			to:    time.Date(2024, 7, 1, 9, 0, 30, 0, time.UTC),
+			want:  true,
+		},
+		{
+			name:  "local time on the previous UTC day",
+			times: []domain.DeliveryTime{{Hour: 6}},
+			loc:   novosibirsk,
+			from:  time.Date(2024, 7, 1, 22, 59, 30, 0, time.UTC),
+			to:    time.Date(2024, 7, 1, 23, 0, 30, 0, time.UTC),
+			want:  true,
+		},
+		{
+			name:  "server time is not the local time",
+			times: []domain.DeliveryTime{{Hour: 6}},
+			loc:   novosibirsk,
+			from:  time.Date(2024, 7, 1, 5, 59, 30, 0, time.UTC),
+			to:    time.Date(2024, 7, 1, 6, 0, 30, 0, time.UTC),
+			want:  false,
+		},
+		{
+			name:  "midnight in tick crossing it",
+			times: []domain.DeliveryTime{{Hour: 0}},
+			loc:   novosibirsk,
+			from:  time.Date(2024, 7, 1, 23, 59, 30, 0, novosibirsk),
+			to:    time.Date(2024, 7, 2, 0, 0, 30, 0, novosibirsk),
+			want:  true,
+		},
+		{
+			name:  "time before tick crossing midnight",
+			times: []domain.DeliveryTime{{Hour: 23, Minute: 59}},
+			loc:   novosibirsk,
+			from:  time.Date(2024, 7, 1, 23, 59, 30, 0, novosibirsk),
+			to:    time.Date(2024, 7, 2, 0, 0, 30, 0, novosibirsk),
+			want:  false,
+		},
+		{
+			name:    "weekly on the weekday",
+			times:   []domain.DeliveryTime{{Hour: 19}},
+			weekday: &sunday,
+			loc:     novosibirsk,
+			from:    time.Date(2024, 7, 7, 18, 59, 30, 0, novosibirsk),
+			to:      time.Date(2024, 7, 7, 19, 0, 30, 0, novosibirsk),
+			want:    true,
+		},
+		{
+			name:    "weekly on another weekday",
+			times:   []domain.DeliveryTime{{Hour: 19}},
+			weekday: &sunday,
+			loc:     novosibirsk,
+			from:    time.Date(2024, 7, 6, 18, 59, 30, 0, novosibirsk),
+			to:      time.Date(2024, 7, 6, 19, 0, 30, 0, novosibirsk),
+			want:    false,
+		},
+		{
+			name:    "weekly on the local weekday which is another UTC one",
+			times:   []domain.DeliveryTime{{Hour: 6}},
+			weekday: &sunday,
+			loc:     novosibirsk,
+			from:    time.Date(2024, 7, 6, 22, 59, 30, 0, time.UTC), // Saturday in UTC
+			to:      time.Date(2024, 7, 6, 23, 0, 30, 0, time.UTC),
+			want:    true,
+		},
+		{
+			name:    "weekly at midnight starting the weekday",
+			times:   []domain.DeliveryTime{{Hour: 0}},
+			weekday: &sunday,
+			loc:     novosibirsk,
+			from:    time.Date(2024, 7, 6, 23, 59, 30, 0, novosibirsk),
+			to:      time.Date(2024, 7, 7, 0, 0, 30, 0, novosibirsk),
+			want:    true,
+		},
+		{
+			name:    "weekly at midnight ending the weekday",
+			times:   []domain.DeliveryTime{{Hour: 0}},
+			weekday: &sunday,
+			loc:     novosibirsk,
+			from:    time.Date(2024, 7, 7, 23, 59, 30, 0, novosibirsk),
+			to:      time.Date(2024, 7, 8, 0, 0, 30, 0, novosibirsk),
+			want:    false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isDue(tt.times, tt.weekday, tt.loc, tt.from, tt.to); got != tt.want {
+				t.Errorf("isDue() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestIsDueOncePerDay runs the ticks of whole days, the delivery must fire exactly once a day also on the days of the
+// DST transitions, including the times skipped or repeated by them
+func TestIsDueOncePerDay(t *testing.T) {
+	berlin := mustLoadLocation(t, "Europe/Berlin")
+	newYork := mustLoadLocation(t, "America/New_York")
+
+	tests := []struct {
+		name string
+		time domain.DeliveryTime
+		loc  *time.Location
+		day  time.Time
+	}{
+		{"regular day", domain.DeliveryTime{Hour: 9}, berlin, time.Date(2024, 7, 1, 0, 0, 0, 0, berlin)},
+		{"spring forward", domain.DeliveryTime{Hour: 9}, berlin, time.Date(2024, 3, 31, 0, 0, 0, 0, berlin)},
+		{"spring forward skipped time", domain.DeliveryTime{Hour: 2, Minute: 30}, berlin, time.Date(2024, 3, 31, 0, 0, 0, 0, berlin)},
+		{"fall back", domain.DeliveryTime{Hour: 9}, berlin, time.Date(2024, 10, 27, 0, 0, 0, 0, berlin)},
+		{"fall back repeated time", domain.DeliveryTime{Hour: 2, Minute: 30}, berlin, time.Date(2024, 10, 27, 0, 0, 0, 0, berlin)},
+		{"fall back midnight", domain.DeliveryTime{Hour: 0}, newYork, time.Date(2024, 11, 3, 0, 0, 0, 0, newYork)},
+		{"fall back repeated time in America", domain.DeliveryTime{Hour: 1, Minute: 30}, newYork, time.Date(2024, 11, 3, 0, 0, 0, 0, newYork)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// The ticks are not aligned to minutes, as the ticker isn't
+			from := tt.day.Add(-30 * time.Second)
+			end := time.Date(tt.day.Year(), tt.day.Month(), tt.day.Day()+1, 0, 0, 0, 0, tt.loc).Add(-30 * time.Second)
+
+			fired := 0
+			for from.Before(end) {
+				to := from.Add(schedulerTickInterval)
+				if isDue([]domain.DeliveryTime{tt.time}, nil, tt.loc, from, to) {
+					fired++
+				}
+				from = to
+			}
+
+			if fired != 1 {
+				t.Errorf("fired %d times on %s, want once", fired, tt.day.Format(time.DateOnly))
+			}
+		})
+	}
+}
+
+type subscriptionsStub []domain.Subscription
+
+func (s subscriptionsStub) FetchSubscriptions(context.Context, domain.Topic) ([]domain.Subscription, error) {
+	return s, nil
+}
+
+func TestSchedulerDueChatsNow(t *testing.T) {
+	novosibirsk := mustLoadLocation(t, "Asia/Novosibirsk")
+	s := NewScheduler(domain.TopicHoliday, []domain.DeliveryTime{{Hour: 6}}, subscriptionsStub{
+		{ChatID: 1, Location: novosibirsk},
+		{ChatID: 2, Location: time.UTC},
+	})
+
+	from := time.Date(2024, 7, 1, 22, 59, 30, 0, time.UTC)
+	to := from.Add(schedulerTickInterval)
+
+	chats, err := s.dueChats(context.Background(), from, to)
+	if err != nil {
+		t.Fatalf("dueChats() error = %v", err)
+	}
+	if len(chats) != 1 || chats[0].ID != 1 {
+		t.Fatalf("dueChats() = %v, want chat 1", chats)
+	}
+
+	// The report of the chat is for its July 2, the server is still on July 1
+	if got := chats[0].Now.Format(time.DateTime); got != "2024-07-02 06:00:30" {
+		t.Errorf("Now = %s, want 2024-07-02 06:00:30 in the chat's timezone", got)
+	}
+}
+
+func mustLoadLocation(t *testing.T, name string) *time.Location {
+	t.Helper()
+
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Fatalf("loading location %s: %v", name, err)
+	}
+	return loc
+}
@@ -23,6 +23,7 @@ type Bot interface {
 
 type CommandDispatcher interface {
 	ExecuteCommands(update tgbotapi.Update)
+	ExecuteCallback(query tgbotapi.CallbackQuery)
 }
 
 type service struct {
@@ -81,6 +82,20 @@ func (svc *service) handleUpdate(update tgbotapi.Update) {
 
 		svc.commandDispatcher.ExecuteCommands(update)
 	}
+
+	if query := update.CallbackQuery; query != nil {
+		slog.Info("callback query received", "user", query.From, "data", query.Data)
+
+		if !svc.authenticator.IsAuthorized(query.From.ID) {
+			svc.messages <- &domain.CallbackAnswer{
+				CallbackQueryID: query.ID,
+				Text:            fmt.Sprintf("User ID %d not authorized to use this bot.", query.From.ID),
+			}
+			return
+		}
+
+		svc.commandDispatcher.ExecuteCallback(*query)
+	}
 }
 
 func (svc *service) handleMessage(message domain.Message) {
@@ -2,7 +2,9 @@ import requests
 import os
 
 def send_html_files(directory):
-    url = 'http://localhost:9090/api/holidays/import'
+    url = os.environ.get('HOLIDAYS_IMPORT_URL', 'http://localhost:8080/api/holidays/import')
+    token = os.environ.get('HOLIDAYS_IMPORT_TOKEN')
+    headers = {'Authorization': f'Bearer {token}'} if token else {}
     # File to keep track of successfully uploaded files
     log_file_path = os.path.join(directory, 'upload_log.txt')
 
@@ -18,7 +20,7 @@ def send_html_files(directory):
             filepath = os.path.join(directory, filename)
             with open(filepath, 'rb') as file:
                 files = {'file': (filename, file)}
-                response = requests.post(url, files=files)
+                response = requests.post(url, files=files, headers=headers)
                 if response.status_code == 200:
                     print(f"Successfully uploaded {filename}")
                     # Add filename to uploaded files and update log file